@@ -0,0 +1,169 @@
+// Package asynctest contains helpers for testing code that uses tasks.
+//
+// The timeouts given to these helpers are always measured in real time,
+// even if the default clock has been replaced with a fake clock.
+package asynctest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/task"
+)
+
+// AssertResolves fails the test if the task does not resolve within the
+// timeout, the resolved value is returned for further assertions.
+func AssertResolves(tb testing.TB, t *task.Task, timeout time.Duration) interface{} {
+	tb.Helper()
+	if !waitFor(t, timeout) {
+		tb.Errorf("asynctest: task did not resolve within %s, it is %s", timeout, t.State())
+		return nil
+	}
+	v, err := t.Result()
+	if t.State() != task.StateResolved {
+		tb.Errorf("asynctest: expected task to resolve but it is %s: %v", t.State(), err)
+	}
+	return v
+}
+
+// AssertRejectsWith fails the test if the task does not reject within the
+// timeout with an error that matches target. An error matches if errors.Is
+// reports true or if an error of the same type as target is found in the
+// chain, so error types can be matched with a zero value,
+// eg: `asynctest.AssertRejectsWith(t, tsk, &task.ErrTaskStopped{}, time.Second)`
+//
+// The rejected error is returned for further assertions.
+func AssertRejectsWith(tb testing.TB, t *task.Task, target error, timeout time.Duration) error {
+	tb.Helper()
+	if !waitFor(t, timeout) {
+		tb.Errorf("asynctest: task did not reject within %s, it is %s", timeout, t.State())
+		return nil
+	}
+	_, err := t.Result()
+	if err == nil {
+		tb.Errorf("asynctest: expected task to reject but it is %s", t.State())
+		return nil
+	}
+	if !matches(err, target) {
+		tb.Errorf("asynctest: expected task to reject with %T but got: %v", target, err)
+	}
+	return err
+}
+
+// AssertStopsWithin asks the task to stop & fails the test
+// if it has not stopped once the timeout has passed.
+func AssertStopsWithin(tb testing.TB, t *task.Task, timeout time.Duration) {
+	tb.Helper()
+	go t.Stop()
+	if !waitFor(t, timeout) {
+		tb.Errorf("asynctest: task did not stop within %s", timeout)
+	}
+}
+
+// Eventually fails the test if condition does not return true within the
+// timeout, condition is called every interval until it returns true.
+func Eventually(tb testing.TB, condition func() bool, timeout, interval time.Duration) {
+	tb.Helper()
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if condition() {
+			return
+		}
+		select {
+		case <-deadline.C:
+			if !condition() {
+				tb.Errorf("asynctest: condition was not met within %s", timeout)
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckLeaks fails the test if any task created after it was called is still
+// running once the test has finished, call it at the start of a test. Tasks
+// are given up to a second to finish after the test, see CheckLeaksWithin.
+//
+// Do not use this with parallel tests, tasks created by other tests running
+// at the same time would be reported as leaks.
+func CheckLeaks(tb testing.TB) {
+	tb.Helper()
+	CheckLeaksWithin(tb, time.Second)
+}
+
+// CheckLeaksWithin does the same as CheckLeaks but with
+// the given grace period for tasks to finish.
+func CheckLeaksWithin(tb testing.TB, grace time.Duration) {
+	tb.Helper()
+	tracker := task.NewTracker()
+	tb.Cleanup(func() {
+		tracker.Close()
+
+		deadline := time.Now().Add(grace)
+		live := tracker.Live()
+		for len(live) > 0 && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+			live = tracker.Live()
+		}
+
+		for _, t := range live {
+			created, _ := t.EnteredAt(task.StatePending)
+			tb.Errorf("asynctest: leaked task is %s, created at %s", t.State(), created.Format(time.RFC3339Nano))
+		}
+	})
+}
+
+// Fake is a task that resolves, rejects or is canceled on command, create
+// new instances with NewFake. It is a task.Source that also records if it
+// has been asked to stop, useful to check the code under test stops tasks.
+type Fake struct {
+	*task.Source
+}
+
+// NewFake creates a new pending Fake.
+func NewFake() *Fake {
+	return &Fake{Source: task.NewSource()}
+}
+
+// StopRequested returns true if the task has been asked to stop.
+func (f *Fake) StopRequested() bool {
+	select {
+	case <-*f.Task().Stopper:
+		return true
+	default:
+		return false
+	}
+}
+
+// waitFor returns true if the task is done within the timeout
+func waitFor(t *task.Task, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-*t.Done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// matches returns true if err is target or contains an error of the same type
+func matches(err, target error) bool {
+	if target == nil || errors.Is(err, target) {
+		return true
+	}
+	targetType := reflect.TypeOf(target)
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if reflect.TypeOf(e) == targetType {
+			return true
+		}
+	}
+	return false
+}
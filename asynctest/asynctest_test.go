@@ -0,0 +1,165 @@
+package asynctest_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/asynctest"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder is a testing.TB that records failures & cleanups rather than acting on them
+type recorder struct {
+	testing.TB
+	errors   []string
+	cleanups []func()
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Cleanup(fn func()) {
+	r.cleanups = append(r.cleanups, fn)
+}
+
+// finish runs the recorded cleanups the same way testing does, last first
+func (r *recorder) finish() {
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.cleanups[i]()
+	}
+}
+
+func TestAssertResolves(t *testing.T) {
+	r := &recorder{TB: t}
+	assert.Equal(t, 1, asynctest.AssertResolves(r, task.Resolved(1), time.Second))
+	assert.Empty(t, r.errors)
+
+	asynctest.AssertResolves(r, task.Rejected(errors.New("boom")), time.Second)
+	assert.Len(t, r.errors, 1)
+
+	asynctest.AssertResolves(r, task.New(func() {}), time.Second)
+	assert.Len(t, r.errors, 2)
+
+	f := asynctest.NewFake()
+	defer f.Cancel()
+	assert.Nil(t, asynctest.AssertResolves(r, f.Task(), 10*time.Millisecond))
+	assert.Len(t, r.errors, 3)
+	assert.Contains(t, r.errors[2], "did not resolve within 10ms")
+}
+
+func TestAssertRejectsWith(t *testing.T) {
+	r := &recorder{TB: t}
+
+	// Matched with errors.Is
+	err := asynctest.AssertRejectsWith(r, task.Rejected(io.EOF), io.EOF, time.Second)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Empty(t, r.errors)
+
+	// Matched by type, the zero value target is not the rejected error
+	f := asynctest.NewFake()
+	f.Cancel()
+	err = asynctest.AssertRejectsWith(r, f.Task(), &task.ErrTaskStopped{}, time.Second)
+	var stopped *task.ErrTaskStopped
+	assert.ErrorAs(t, err, &stopped)
+	assert.Empty(t, r.errors)
+
+	// Any error matches a nil target
+	asynctest.AssertRejectsWith(r, task.Rejected(errors.New("boom")), nil, time.Second)
+	assert.Empty(t, r.errors)
+
+	asynctest.AssertRejectsWith(r, task.Rejected(errors.New("boom")), &task.ErrTaskStopped{}, time.Second)
+	assert.Len(t, r.errors, 1)
+	assert.Contains(t, r.errors[0], "*task.ErrTaskStopped")
+
+	asynctest.AssertRejectsWith(r, task.Resolved(1), nil, time.Second)
+	assert.Len(t, r.errors, 2)
+
+	f = asynctest.NewFake()
+	defer f.Cancel()
+	asynctest.AssertRejectsWith(r, f.Task(), nil, 10*time.Millisecond)
+	assert.Len(t, r.errors, 3)
+}
+
+func TestAssertStopsWithin(t *testing.T) {
+	r := &recorder{TB: t}
+	asynctest.AssertStopsWithin(r, task.New(func(t *task.Internal) { <-*t.Stopper }), time.Second)
+	assert.Empty(t, r.errors)
+
+	release := make(chan struct{})
+	defer close(release)
+	running := make(chan struct{})
+	stubborn := task.New(func() {
+		close(running)
+		<-release
+	})
+	<-running
+	asynctest.AssertStopsWithin(r, stubborn, 10*time.Millisecond)
+	assert.Len(t, r.errors, 1)
+}
+
+func TestEventually(t *testing.T) {
+	r := &recorder{TB: t}
+	var n int64
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		atomic.StoreInt64(&n, 1)
+	}()
+	asynctest.Eventually(r, func() bool { return atomic.LoadInt64(&n) == 1 }, time.Second, time.Millisecond)
+	assert.Empty(t, r.errors)
+
+	asynctest.Eventually(r, func() bool { return false }, 10*time.Millisecond, time.Millisecond)
+	assert.Len(t, r.errors, 1)
+}
+
+func TestCheckLeaks(t *testing.T) {
+	r := &recorder{TB: t}
+	asynctest.CheckLeaksWithin(r, 10*time.Millisecond)
+	f := asynctest.NewFake()
+	r.finish()
+	assert.Len(t, r.errors, 1)
+	assert.Contains(t, r.errors[0], "leaked task is running")
+	f.Cancel()
+
+	// Tasks created before CheckLeaks are not reported
+	r = &recorder{TB: t}
+	f = asynctest.NewFake()
+	defer f.Cancel()
+	asynctest.CheckLeaksWithin(r, 10*time.Millisecond)
+	r.finish()
+	assert.Empty(t, r.errors)
+}
+
+func TestCheckLeaksGracePeriod(t *testing.T) {
+	// The task finishes after the test but within the grace period
+	r := &recorder{TB: t}
+	asynctest.CheckLeaksWithin(r, time.Second)
+	task.New(func() { time.Sleep(50 * time.Millisecond) })
+	start := time.Now()
+	r.finish()
+	assert.Empty(t, r.errors)
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(20*time.Millisecond))
+}
+
+func TestFake(t *testing.T) {
+	f := asynctest.NewFake()
+	assert.False(t, f.StopRequested())
+	assert.True(t, f.Resolve(1))
+	assert.False(t, f.Reject(errors.New("too late")))
+	v, err := f.Task().Result()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+
+	f = asynctest.NewFake()
+	go f.Task().Stop()
+	asynctest.Eventually(t, f.StopRequested, time.Second, time.Millisecond)
+	var stopped *task.ErrTaskStopped
+	assert.ErrorAs(t, f.Task().Wait(), &stopped)
+}
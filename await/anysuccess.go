@@ -0,0 +1,82 @@
+package await
+
+import (
+	"time"
+
+	"github.com/brad-jones/goasync/v2/stop"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/brad-jones/goerr/v2"
+)
+
+// AnySuccess will wait for the first task to resolve and return that value,
+// stopping all other tasks. Tasks that reject are ignored unless every task
+// rejects, in which case an ErrTaskFailed containing every error is returned.
+func AnySuccess(awaitables ...*task.Task) (interface{}, error) {
+	defer stop.All(awaitables...)
+	return anySuccess(awaitables)
+}
+
+// MustAnySuccess does the same thing as AnySuccess but panics if an error is encountered
+func MustAnySuccess(awaitables ...*task.Task) interface{} {
+	v, e := AnySuccess(awaitables...)
+	goerr.Check(e)
+	return v
+}
+
+// AnySuccessAsync does the same thing as AnySuccess but does so asynchronously
+func AnySuccessAsync(awaitables ...*task.Task) *task.Task {
+	return task.New(func(t *task.Internal) {
+		t.Settle(AnySuccess(awaitables...))
+	})
+}
+
+// AnySuccessWithTimeout does the same as AnySuccess but allows you to set a
+// timeout for waiting for other tasks to stop.
+func AnySuccessWithTimeout(timeout time.Duration, awaitables ...*task.Task) (interface{}, error) {
+	defer stop.AllWithTimeout(timeout, awaitables...)
+	return anySuccess(awaitables)
+}
+
+// MustAnySuccessWithTimeout does the same thing as AnySuccessWithTimeout but panics if an error is encountered
+func MustAnySuccessWithTimeout(timeout time.Duration, awaitables ...*task.Task) interface{} {
+	v, e := AnySuccessWithTimeout(timeout, awaitables...)
+	goerr.Check(e)
+	return v
+}
+
+// AnySuccessWithTimeoutAsync does the same thing as AnySuccessWithTimeout but does so asynchronously
+func AnySuccessWithTimeoutAsync(timeout time.Duration, awaitables ...*task.Task) *task.Task {
+	return task.New(func(t *task.Internal) {
+		t.Settle(AnySuccessWithTimeout(timeout, awaitables...))
+	})
+}
+
+// FastAnySuccess does the same as AnySuccess but does not wait for all other
+// tasks to stop, it does tell them to stop it just doesn't wait for them to stop.
+func FastAnySuccess(awaitables ...*task.Task) (interface{}, error) {
+	defer stop.AllAsync(awaitables...)
+	return anySuccess(awaitables)
+}
+
+// MustFastAnySuccess does the same thing as FastAnySuccess but panics if an error is encountered
+func MustFastAnySuccess(awaitables ...*task.Task) interface{} {
+	v, e := FastAnySuccess(awaitables...)
+	goerr.Check(e)
+	return v
+}
+
+// FastAnySuccessAsync does the same thing as FastAnySuccess but does so asynchronously
+func FastAnySuccessAsync(awaitables ...*task.Task) *task.Task {
+	return task.New(func(t *task.Internal) {
+		t.Settle(FastAnySuccess(awaitables...))
+	})
+}
+
+// anySuccess is simply a quorum of one
+func anySuccess(awaitables []*task.Task) (interface{}, error) {
+	outcomes, err := some(1, awaitables)
+	if err != nil {
+		return nil, goerr.Wrap(err)
+	}
+	return outcomes[0].Value, nil
+}
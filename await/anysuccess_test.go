@@ -0,0 +1,40 @@
+package await_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/brad-jones/goasync/v2/await"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnySuccessIgnoresRejections(t *testing.T) {
+	s, tasks := sources(3)
+	go func() {
+		s[0].Reject(errors.New("boom"))
+		tasks[0].Wait()
+		s[1].Resolve("b")
+	}()
+
+	v, err := await.AnySuccess(tasks...)
+	assert.NoError(t, err)
+	assert.Equal(t, "b", v)
+	assert.Equal(t, task.StateStopped, tasks[2].State())
+}
+
+func TestAnySuccessWhenEveryTaskRejects(t *testing.T) {
+	_, err := await.AnySuccess(
+		task.Rejected(errors.New("a")),
+		task.New(func() { panic("b") }),
+		task.Rejected(errors.New("c")),
+	)
+	var failed *await.ErrTaskFailed
+	if assert.ErrorAs(t, err, &failed) {
+		assert.Len(t, failed.Errors, 3)
+		assert.ElementsMatch(t, []int{0, 1, 2}, failed.Indexes)
+	}
+
+	_, err = await.AnySuccess()
+	assert.ErrorAs(t, err, &failed)
+}
@@ -14,18 +14,21 @@ import (
 func All(awaitables ...*task.Task) ([]interface{}, error) {
 	awaited := []interface{}{}
 	awaitedErrors := []error{}
+	awaitedIndexes := []int{}
 
-	for _, awaitable := range awaitables {
+	for i, awaitable := range awaitables {
 		v, e := awaitable.Result()
 		if e != nil {
 			awaitedErrors = append(awaitedErrors, goerr.Wrap(e))
+			awaitedIndexes = append(awaitedIndexes, i)
 		}
 		awaited = append(awaited, v)
 	}
 
 	if len(awaitedErrors) > 0 {
 		return nil, goerr.Wrap(&ErrTaskFailed{
-			Errors: awaitedErrors,
+			Errors:  awaitedErrors,
+			Indexes: awaitedIndexes,
 		})
 	}
 
@@ -42,7 +45,7 @@ func MustAll(awaitables ...*task.Task) []interface{} {
 // AllAsync does the same thing as All but does so asynchronously
 func AllAsync(awaitables ...*task.Task) *task.Task {
 	return task.New(func(t *task.Internal) {
-		t.Resolve(MustAll(awaitables...))
+		t.Settle(All(awaitables...))
 	})
 }
 
@@ -105,7 +108,7 @@ func MustAllOrError(awaitables ...*task.Task) []interface{} {
 // AllOrErrorAsync does the same thing as AllOrError but does so asynchronously
 func AllOrErrorAsync(awaitables ...*task.Task) *task.Task {
 	return task.New(func(t *task.Internal) {
-		t.Resolve(MustAllOrError(awaitables...))
+		t.Settle(AllOrError(awaitables...))
 	})
 }
 
@@ -168,7 +171,7 @@ func MustAllOrErrorWithTimeout(timeout time.Duration, awaitables ...*task.Task)
 // AllOrErrorWithTimeoutAsync does the same thing as AllOrErrorWithTimeout but does so asynchronously
 func AllOrErrorWithTimeoutAsync(timeout time.Duration, awaitables ...*task.Task) *task.Task {
 	return task.New(func(t *task.Internal) {
-		t.Resolve(MustAllOrErrorWithTimeout(timeout, awaitables...))
+		t.Settle(AllOrErrorWithTimeout(timeout, awaitables...))
 	})
 }
 
@@ -231,7 +234,7 @@ func MustFastAllOrError(awaitables ...*task.Task) []interface{} {
 // FastAllOrErrorAsync does the same thing as FastAllOrError but does so asynchronously
 func FastAllOrErrorAsync(awaitables ...*task.Task) *task.Task {
 	return task.New(func(t *task.Internal) {
-		t.Resolve(MustFastAllOrError(awaitables...))
+		t.Settle(FastAllOrError(awaitables...))
 	})
 }
 
@@ -278,7 +281,7 @@ func MustAny(awaitables ...*task.Task) interface{} {
 // AnyAsync does the same thing as Any but does so asynchronously
 func AnyAsync(awaitables ...*task.Task) *task.Task {
 	return task.New(func(t *task.Internal) {
-		t.Resolve(MustAny(awaitables...))
+		t.Settle(Any(awaitables...))
 	})
 }
 
@@ -325,7 +328,7 @@ func MustAnyWithTimeout(timeout time.Duration, awaitables ...*task.Task) interfa
 // AnyWithTimeoutAsync does the same thing as AnyWithTimeout but does so asynchronously
 func AnyWithTimeoutAsync(timeout time.Duration, awaitables ...*task.Task) *task.Task {
 	return task.New(func(t *task.Internal) {
-		t.Resolve(MustAnyWithTimeout(timeout, awaitables...))
+		t.Settle(AnyWithTimeout(timeout, awaitables...))
 	})
 }
 
@@ -372,13 +375,17 @@ func MustFastAny(awaitables ...*task.Task) interface{} {
 // FastAnyAsync does the same thing as FastAny but does so asynchronously
 func FastAnyAsync(awaitables ...*task.Task) *task.Task {
 	return task.New(func(t *task.Internal) {
-		t.Resolve(MustFastAny(awaitables...))
+		t.Settle(FastAny(awaitables...))
 	})
 }
 
 // ErrTaskFailed is returned by the All methods when at least one task returns an error.
 type ErrTaskFailed struct {
 	Errors []error
+
+	// The index of the task that returned each error, in the same order as
+	// Errors. Awaiters that return early, such as AllOrError, do not set this.
+	Indexes []int
 }
 
 func (e *ErrTaskFailed) Error() string {
@@ -0,0 +1,195 @@
+package await
+
+import (
+	"github.com/brad-jones/goasync/v2/stop"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/brad-jones/goerr/v2"
+)
+
+// Map calls fn for each item & waits for every returned task to emit a
+// result, with at most concurrency tasks running at once. A concurrency of
+// zero or less means there is no limit. The results will be returned in a
+// slice ordered the same as items.
+//
+// If any task fails every other task is still awaited & the results are
+// returned along with an ErrTaskFailed, much like AllSettled. The results
+// of the failed tasks are nil & the indexes of the ErrTaskFailed are
+// indexes of items.
+//
+// For example:
+// 	results, err := await.Map(urls, 16, func(url string) *task.Task {
+// 		return downloadAsync(url)
+// 	})
+func Map[T any](items []T, concurrency int, fn func(item T) *task.Task) ([]interface{}, error) {
+	return mapItems(items, concurrency, fn, false, nil)
+}
+
+// MustMap does the same thing as Map but panics if an error is encountered
+func MustMap[T any](items []T, concurrency int, fn func(item T) *task.Task) []interface{} {
+	v, e := Map(items, concurrency, fn)
+	goerr.Check(e)
+	return v
+}
+
+// MapAsync does the same thing as Map but does so asynchronously,
+// stopping the returned task will stop all running tasks.
+func MapAsync[T any](items []T, concurrency int, fn func(item T) *task.Task) *task.Task {
+	return task.New(func(t *task.Internal) {
+		t.Settle(mapItems(items, concurrency, fn, false, *t.Stopper))
+	})
+}
+
+// MapOrError does the same as Map but returns as soon as an error is
+// encountered, no more tasks are created & all running tasks are stopped.
+func MapOrError[T any](items []T, concurrency int, fn func(item T) *task.Task) ([]interface{}, error) {
+	return mapItems(items, concurrency, fn, true, nil)
+}
+
+// MustMapOrError does the same thing as MapOrError but panics if an error is encountered
+func MustMapOrError[T any](items []T, concurrency int, fn func(item T) *task.Task) []interface{} {
+	v, e := MapOrError(items, concurrency, fn)
+	goerr.Check(e)
+	return v
+}
+
+// MapOrErrorAsync does the same thing as MapOrError but does so asynchronously,
+// stopping the returned task will stop all running tasks.
+func MapOrErrorAsync[T any](items []T, concurrency int, fn func(item T) *task.Task) *task.Task {
+	return task.New(func(t *task.Internal) {
+		t.Settle(mapItems(items, concurrency, fn, true, *t.Stopper))
+	})
+}
+
+// ForEach does the same as Map but discards the results.
+func ForEach[T any](items []T, concurrency int, fn func(item T) *task.Task) error {
+	_, err := mapItems(items, concurrency, fn, false, nil)
+	return err
+}
+
+// MustForEach does the same thing as ForEach but panics if an error is encountered
+func MustForEach[T any](items []T, concurrency int, fn func(item T) *task.Task) {
+	goerr.Check(ForEach(items, concurrency, fn))
+}
+
+// ForEachAsync does the same thing as ForEach but does so asynchronously,
+// stopping the returned task will stop all running tasks.
+func ForEachAsync[T any](items []T, concurrency int, fn func(item T) *task.Task) *task.Task {
+	return task.New(func(t *task.Internal) {
+		_, err := mapItems(items, concurrency, fn, false, *t.Stopper)
+		if err != nil {
+			t.Reject(err)
+		}
+	})
+}
+
+// ForEachOrError does the same as MapOrError but discards the results.
+func ForEachOrError[T any](items []T, concurrency int, fn func(item T) *task.Task) error {
+	_, err := mapItems(items, concurrency, fn, true, nil)
+	return err
+}
+
+// MustForEachOrError does the same thing as ForEachOrError but panics if an error is encountered
+func MustForEachOrError[T any](items []T, concurrency int, fn func(item T) *task.Task) {
+	goerr.Check(ForEachOrError(items, concurrency, fn))
+}
+
+// ForEachOrErrorAsync does the same thing as ForEachOrError but does so asynchronously,
+// stopping the returned task will stop all running tasks.
+func ForEachOrErrorAsync[T any](items []T, concurrency int, fn func(item T) *task.Task) *task.Task {
+	return task.New(func(t *task.Internal) {
+		_, err := mapItems(items, concurrency, fn, true, *t.Stopper)
+		if err != nil {
+			t.Reject(err)
+		}
+	})
+}
+
+// mapItems does the actual work for Map, ForEach & their variants. Once
+// failFast is triggered or stopper is closed no more tasks are created &
+// the running tasks are stopped, it always waits for running tasks to finish.
+func mapItems[T any](items []T, concurrency int, fn func(item T) *task.Task, failFast bool, stopper <-chan struct{}) ([]interface{}, error) {
+	if concurrency <= 0 {
+		concurrency = len(items)
+	}
+
+	type finished struct {
+		index     int
+		awaitable *task.Task
+	}
+
+	results := make([]interface{}, len(items))
+	errs := make([]error, len(items))
+	running := map[int]*task.Task{}
+	finishedCh := make(chan finished)
+	var firstErr error
+	stopped := false
+
+	stopRunning := func() {
+		awaitables := []*task.Task{}
+		for _, awaitable := range running {
+			awaitables = append(awaitables, awaitable)
+		}
+		stop.All(awaitables...)
+	}
+
+	for next := 0; next < len(items) || len(running) > 0; {
+		for firstErr == nil && !stopped && next < len(items) && len(running) < concurrency {
+			awaitable := fn(items[next])
+			running[next] = awaitable
+			go func(i int, awaitable *task.Task) {
+				<-*awaitable.Done
+				finishedCh <- finished{i, awaitable}
+			}(next, awaitable)
+			next++
+		}
+		if len(running) == 0 {
+			break
+		}
+
+		select {
+		case f := <-finishedCh:
+			delete(running, f.index)
+			v, err := f.awaitable.Result()
+			if firstErr != nil || stopped {
+				continue
+			}
+			if err != nil {
+				errs[f.index] = goerr.Wrap(err)
+				if failFast {
+					firstErr = errs[f.index]
+					stopRunning()
+				}
+				continue
+			}
+			results[f.index] = v
+		case <-stopper:
+			stopper = nil
+			stopped = true
+			stopRunning()
+		}
+	}
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if stopped {
+		return nil, goerr.Wrap(&task.ErrTaskStopped{})
+	}
+
+	awaitedErrors := []error{}
+	awaitedIndexes := []int{}
+	for i, err := range errs {
+		if err != nil {
+			awaitedErrors = append(awaitedErrors, err)
+			awaitedIndexes = append(awaitedIndexes, i)
+		}
+	}
+	if len(awaitedErrors) > 0 {
+		return results, goerr.Wrap(&ErrTaskFailed{
+			Errors:  awaitedErrors,
+			Indexes: awaitedIndexes,
+		})
+	}
+
+	return results, nil
+}
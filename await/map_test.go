@@ -0,0 +1,109 @@
+package await_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/await"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMap(t *testing.T) {
+	var live, max int64
+	items := []int{}
+	for i := 0; i < 20; i++ {
+		items = append(items, i)
+	}
+
+	results, err := await.Map(items, 4, func(i int) *task.Task {
+		return task.New(func(t *task.Internal) {
+			n := atomic.AddInt64(&live, 1)
+			for {
+				m := atomic.LoadInt64(&max)
+				if n <= m || atomic.CompareAndSwapInt64(&max, m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt64(&live, -1)
+			t.Resolve(i * 2)
+		})
+	})
+	assert.NoError(t, err)
+	for i, v := range results {
+		assert.Equal(t, i*2, v)
+	}
+	assert.LessOrEqual(t, atomic.LoadInt64(&max), int64(4))
+}
+
+func TestMapAwaitsEveryTask(t *testing.T) {
+	results, err := await.Map([]int{0, 1, 2, 3}, 2, func(i int) *task.Task {
+		if i%2 == 1 {
+			return task.Rejected(errors.New("odd"))
+		}
+		return task.Resolved(i * 10)
+	})
+	assert.Equal(t, []interface{}{0, nil, 20, nil}, results)
+	var failed *await.ErrTaskFailed
+	if assert.ErrorAs(t, err, &failed) {
+		assert.Equal(t, []int{1, 3}, failed.Indexes)
+	}
+}
+
+func TestMapOrErrorStopsInFlightWork(t *testing.T) {
+	mu := sync.Mutex{}
+	created := []*task.Task{}
+	started := make(chan struct{}, 3)
+
+	_, err := await.MapOrError([]int{0, 1, 2, 3, 4, 5}, 3, func(i int) *task.Task {
+		var t *task.Task
+		if i == 0 {
+			// Fails once the other tasks are running
+			t = task.New(func(t *task.Internal) {
+				for j := 0; j < 2; j++ {
+					<-started
+				}
+				t.Reject(errors.New("boom"))
+			})
+		} else {
+			t = task.New(func(t *task.Internal) {
+				started <- struct{}{}
+				<-*t.Stopper
+			})
+		}
+		mu.Lock()
+		created = append(created, t)
+		mu.Unlock()
+		return t
+	})
+
+	assert.EqualError(t, err, "boom")
+	mu.Lock()
+	defer mu.Unlock()
+	if assert.Len(t, created, 3, "no more tasks are created after the error") {
+		assert.Equal(t, task.StateRejected, created[0].State())
+		assert.Equal(t, task.StateStopped, created[1].State())
+		assert.Equal(t, task.StateStopped, created[2].State())
+	}
+}
+
+func TestMapAsyncStop(t *testing.T) {
+	started := make(chan struct{}, 2)
+	m := await.MapAsync([]int{0, 1, 2, 3}, 2, func(i int) *task.Task {
+		return task.New(func(t *task.Internal) {
+			started <- struct{}{}
+			<-*t.Stopper
+		})
+	})
+	<-started
+	<-started
+	m.Stop()
+
+	var stopped *task.ErrTaskStopped
+	assert.ErrorAs(t, m.Wait(), &stopped)
+	assert.Equal(t, task.StateStopped, m.State())
+}
@@ -0,0 +1,90 @@
+package await
+
+import (
+	"time"
+
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/brad-jones/goerr/v2"
+)
+
+// Outcome records how a single awaited task finished.
+type Outcome struct {
+	// The position of the task in the input
+	Index int
+
+	// The value the task resolved, if any
+	Value interface{}
+
+	// The error the task rejected, if any
+	Err error
+
+	// The final state of the task
+	State task.State
+
+	// How long the task was running for, zero if it never ran
+	Duration time.Duration
+}
+
+// AllSettled will wait for every given task to finish and return an outcome
+// for every task, ordered the same as the input. Unlike All the outcomes are
+// returned even when some tasks fail, in which case an ErrTaskFailed is also
+// returned.
+func AllSettled(awaitables ...*task.Task) ([]*Outcome, error) {
+	outcomes := []*Outcome{}
+	awaitedErrors := []error{}
+	awaitedIndexes := []int{}
+
+	for i, awaitable := range awaitables {
+		o := settled(i, awaitable)
+		if o.Err != nil {
+			awaitedErrors = append(awaitedErrors, goerr.Wrap(o.Err))
+			awaitedIndexes = append(awaitedIndexes, i)
+		}
+		outcomes = append(outcomes, o)
+	}
+
+	if len(awaitedErrors) > 0 {
+		return outcomes, goerr.Wrap(&ErrTaskFailed{
+			Errors:  awaitedErrors,
+			Indexes: awaitedIndexes,
+		})
+	}
+
+	return outcomes, nil
+}
+
+// MustAllSettled does the same thing as AllSettled but panics if an error is encountered
+func MustAllSettled(awaitables ...*task.Task) []*Outcome {
+	v, e := AllSettled(awaitables...)
+	goerr.Check(e)
+	return v
+}
+
+// AllSettledAsync does the same thing as AllSettled but does so asynchronously.
+// The returned task always resolves the outcomes, it never rejects.
+func AllSettledAsync(awaitables ...*task.Task) *task.Task {
+	return task.New(func(t *task.Internal) {
+		v, _ := AllSettled(awaitables...)
+		t.Resolve(v)
+	})
+}
+
+// settled waits for the given task to finish and records it's outcome
+func settled(index int, awaitable *task.Task) *Outcome {
+	v, err := awaitable.Result()
+	state := awaitable.State()
+
+	var duration time.Duration
+	if started, ok := awaitable.EnteredAt(task.StateRunning); ok {
+		finished, _ := awaitable.EnteredAt(state)
+		duration = finished.Sub(started)
+	}
+
+	return &Outcome{
+		Index:    index,
+		Value:    v,
+		Err:      err,
+		State:    state,
+		Duration: duration,
+	}
+}
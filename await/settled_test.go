@@ -0,0 +1,58 @@
+package await_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/await"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllSettled(t *testing.T) {
+	outcomes, err := await.AllSettled(
+		task.New(func(t *task.Internal) {
+			time.Sleep(10 * time.Millisecond)
+			t.Resolve(1)
+		}),
+		task.Rejected(errors.New("boom")),
+		task.New(func() { panic("oops") }),
+		task.New(func() {}),
+	)
+
+	var failed *await.ErrTaskFailed
+	if assert.ErrorAs(t, err, &failed) {
+		assert.Equal(t, []int{1, 2}, failed.Indexes)
+		assert.Len(t, failed.Errors, 2)
+	}
+
+	if assert.Len(t, outcomes, 4) {
+		for i, o := range outcomes {
+			assert.Equal(t, i, o.Index)
+		}
+
+		assert.Equal(t, 1, outcomes[0].Value)
+		assert.Equal(t, task.StateResolved, outcomes[0].State)
+		assert.GreaterOrEqual(t, int64(outcomes[0].Duration), int64(10*time.Millisecond))
+
+		assert.EqualError(t, outcomes[1].Err, "boom")
+		assert.Equal(t, task.StateRejected, outcomes[1].State)
+		assert.Zero(t, outcomes[1].Duration)
+
+		var panicked *task.ErrTaskPanicked
+		assert.ErrorAs(t, outcomes[2].Err, &panicked)
+		assert.Equal(t, task.StatePanicked, outcomes[2].State)
+
+		assert.NoError(t, outcomes[3].Err)
+		assert.Equal(t, task.StateCompleted, outcomes[3].State)
+	}
+}
+
+func TestAllSettledAsyncNeverRejects(t *testing.T) {
+	v, err := await.AllSettledAsync(task.Rejected(errors.New("boom"))).Result()
+	assert.NoError(t, err)
+	if outcomes, ok := v.([]*await.Outcome); assert.True(t, ok) && assert.Len(t, outcomes, 1) {
+		assert.EqualError(t, outcomes[0].Err, "boom")
+	}
+}
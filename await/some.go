@@ -0,0 +1,133 @@
+package await
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/stop"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/brad-jones/goerr/v2"
+)
+
+// Some will wait for n of the given tasks to resolve and return their outcomes
+// in the order they resolved, stopping all other tasks. Each outcome records
+// the index of the task in the input.
+//
+// As soon as enough tasks have failed that n successes are no longer possible
+// an ErrTaskFailed containing every failure is returned.
+func Some(n int, awaitables ...*task.Task) ([]*Outcome, error) {
+	defer stop.All(awaitables...)
+	return some(n, awaitables)
+}
+
+// MustSome does the same thing as Some but panics if an error is encountered
+func MustSome(n int, awaitables ...*task.Task) []*Outcome {
+	v, e := Some(n, awaitables...)
+	goerr.Check(e)
+	return v
+}
+
+// SomeAsync does the same thing as Some but does so asynchronously
+func SomeAsync(n int, awaitables ...*task.Task) *task.Task {
+	return task.New(func(t *task.Internal) {
+		t.Settle(Some(n, awaitables...))
+	})
+}
+
+// SomeWithTimeout does the same as Some but allows you to set a
+// timeout for waiting for other tasks to stop.
+func SomeWithTimeout(timeout time.Duration, n int, awaitables ...*task.Task) ([]*Outcome, error) {
+	defer stop.AllWithTimeout(timeout, awaitables...)
+	return some(n, awaitables)
+}
+
+// MustSomeWithTimeout does the same thing as SomeWithTimeout but panics if an error is encountered
+func MustSomeWithTimeout(timeout time.Duration, n int, awaitables ...*task.Task) []*Outcome {
+	v, e := SomeWithTimeout(timeout, n, awaitables...)
+	goerr.Check(e)
+	return v
+}
+
+// SomeWithTimeoutAsync does the same thing as SomeWithTimeout but does so asynchronously
+func SomeWithTimeoutAsync(timeout time.Duration, n int, awaitables ...*task.Task) *task.Task {
+	return task.New(func(t *task.Internal) {
+		t.Settle(SomeWithTimeout(timeout, n, awaitables...))
+	})
+}
+
+// FastSome does the same as Some but does not wait for all other tasks to stop,
+// it does tell them to stop it just doesn't wait for them to stop.
+func FastSome(n int, awaitables ...*task.Task) ([]*Outcome, error) {
+	defer stop.AllAsync(awaitables...)
+	return some(n, awaitables)
+}
+
+// MustFastSome does the same thing as FastSome but panics if an error is encountered
+func MustFastSome(n int, awaitables ...*task.Task) []*Outcome {
+	v, e := FastSome(n, awaitables...)
+	goerr.Check(e)
+	return v
+}
+
+// FastSomeAsync does the same thing as FastSome but does so asynchronously
+func FastSomeAsync(n int, awaitables ...*task.Task) *task.Task {
+	return task.New(func(t *task.Internal) {
+		t.Settle(FastSome(n, awaitables...))
+	})
+}
+
+// some does the actual waiting for Some & it's variants,
+// stopping the remaining tasks is left up to the caller.
+func some(n int, awaitables []*task.Task) ([]*Outcome, error) {
+	successes := []*Outcome{}
+	awaitedErrors := []error{}
+	awaitedIndexes := []int{}
+
+	failed := func() error {
+		return goerr.Wrap(&ErrTaskFailed{
+			Errors:  awaitedErrors,
+			Indexes: awaitedIndexes,
+		}, fmt.Sprintf("await: %d successful tasks were required but only %d could succeed", n, len(awaitables)-len(awaitedErrors)))
+	}
+
+	if n <= 0 {
+		return successes, nil
+	}
+	if len(awaitables) < n {
+		return nil, failed()
+	}
+
+	doneCh := make(chan struct{}, 1)
+	defer close(doneCh)
+	outcomeCh := make(chan *Outcome, 1)
+
+	for i, awaitable := range awaitables {
+		go func(i int, awaitable *task.Task) {
+			select {
+			case <-*awaitable.Done:
+				select {
+				case outcomeCh <- settled(i, awaitable):
+				case <-doneCh:
+				}
+			case <-doneCh:
+				return
+			}
+		}(i, awaitable)
+	}
+
+	for {
+		o := <-outcomeCh
+		if o.Err == nil {
+			successes = append(successes, o)
+			if len(successes) == n {
+				return successes, nil
+			}
+			continue
+		}
+		awaitedErrors = append(awaitedErrors, goerr.Wrap(o.Err))
+		awaitedIndexes = append(awaitedIndexes, o.Index)
+		if len(awaitables)-len(awaitedErrors) < n {
+			return nil, failed()
+		}
+	}
+}
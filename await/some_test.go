@@ -0,0 +1,68 @@
+package await_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/brad-jones/goasync/v2/await"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/stretchr/testify/assert"
+)
+
+// sources creates n pending sources & their tasks
+func sources(n int) ([]*task.Source, []*task.Task) {
+	s := []*task.Source{}
+	t := []*task.Task{}
+	for i := 0; i < n; i++ {
+		s = append(s, task.NewSource())
+		t = append(t, s[i].Task())
+	}
+	return s, t
+}
+
+func TestSome(t *testing.T) {
+	s, tasks := sources(4)
+	go func() {
+		s[2].Resolve("c")
+		s[1].Reject(errors.New("boom"))
+		s[0].Resolve("a")
+	}()
+
+	outcomes, err := await.Some(2, tasks...)
+	assert.NoError(t, err)
+	values := map[int]interface{}{}
+	for _, o := range outcomes {
+		values[o.Index] = o.Value
+	}
+	assert.Equal(t, map[int]interface{}{0: "a", 2: "c"}, values)
+	assert.Equal(t, task.StateStopped, tasks[3].State())
+}
+
+func TestSomeFailsOnceImpossible(t *testing.T) {
+	// With 2 of 4 tasks rejected 3 successes are impossible,
+	// so Some does not wait for the remaining tasks to settle.
+	s, tasks := sources(4)
+	go func() {
+		s[0].Reject(errors.New("a"))
+		s[3].Reject(errors.New("d"))
+	}()
+
+	outcomes, err := await.Some(3, tasks...)
+	assert.Nil(t, outcomes)
+	var failed *await.ErrTaskFailed
+	if assert.ErrorAs(t, err, &failed) {
+		assert.ElementsMatch(t, []int{0, 3}, failed.Indexes)
+	}
+	assert.Equal(t, task.StateStopped, tasks[1].State())
+	assert.Equal(t, task.StateStopped, tasks[2].State())
+}
+
+func TestSomeEdgeCases(t *testing.T) {
+	outcomes, err := await.Some(0, task.NewSource().Task())
+	assert.NoError(t, err)
+	assert.Empty(t, outcomes)
+
+	var failed *await.ErrTaskFailed
+	_, err = await.Some(2, task.Resolved(1))
+	assert.ErrorAs(t, err, &failed)
+}
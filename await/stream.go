@@ -1,6 +1,11 @@
 package await
 
 import (
+	"iter"
+	"sync"
+	"sync/atomic"
+
+	"github.com/brad-jones/goasync/v2/stop"
 	"github.com/brad-jones/goasync/v2/task"
 )
 
@@ -12,50 +17,89 @@ import (
 // 	for s.Wait() {
 // 		r, err := s.Result()
 // 	}
+//
+// Or using a range over func:
+// 	for r, err := range await.Stream(foo(), bar()).All() {
+// 	}
 func Stream(awaitables ...*task.Task) *StreamInstance {
-	return &StreamInstance{awaitables: awaitables}
+	completed := make(chan *task.Task, len(awaitables))
+	if len(awaitables) == 0 {
+		close(completed)
+	}
+
+	remaining := int64(len(awaitables))
+	for _, v := range awaitables {
+		go func(awaitable *task.Task) {
+			<-*awaitable.Done
+			completed <- awaitable
+			if atomic.AddInt64(&remaining, -1) == 0 {
+				close(completed)
+			}
+		}(v)
+	}
+
+	return &StreamInstance{
+		completed: completed,
+		quit:      make(chan struct{}),
+		stopTasks: func() { stop.All(awaitables...) },
+	}
 }
 
 // StreamInstance is the object that is returned by Stream
 type StreamInstance struct {
-	awaitables []*task.Task
-	current    *task.Task
+	completed <-chan *task.Task
+	current   *task.Task
+
+	// Closed once this stream is no longer being consumed
+	quit     chan struct{}
+	quitOnce sync.Once
+
+	// Streams created by an operator have a parent, the original stream
+	// knows how to stop the awaited tasks instead.
+	parent    *StreamInstance
+	stopTasks func()
 }
 
 // Wait will return true once a task has finished & then remove that task from
 // the list of tasks to wait for. It will return false when there are no more
 // tasks to wait for.
 func (s *StreamInstance) Wait() bool {
-	if len(s.awaitables) == 0 {
+	current, ok := <-s.completed
+	if !ok {
 		return false
 	}
+	s.current = current
+	return true
+}
+
+// C returns a channel that receives each task as it finishes,
+// the channel is closed once every task has been received.
+func (s *StreamInstance) C() <-chan *task.Task {
+	return s.completed
+}
 
-	doneCh := make(chan struct{}, 1)
-	awaitableCh := make(chan *task.Task, 1)
-	for _, v := range s.awaitables {
-		awaitable := v
-		go func() {
-			select {
-			case <-*awaitable.Done:
-				awaitableCh <- awaitable
-				close(awaitableCh)
-			case <-doneCh:
+// All returns an iterator that yields the result of each task as it finishes.
+// Breaking out of the loop early will stop all remaining tasks.
+func (s *StreamInstance) All() iter.Seq2[interface{}, error] {
+	return func(yield func(interface{}, error) bool) {
+		for s.Wait() {
+			if !yield(s.Result()) {
+				s.Stop()
 				return
 			}
-		}()
-	}
-	s.current = <-awaitableCh
-	close(doneCh)
-
-	newAwaitables := []*task.Task{}
-	for _, v := range s.awaitables {
-		if v != s.current {
-			newAwaitables = append(newAwaitables, v)
 		}
 	}
-	s.awaitables = newAwaitables
+}
 
-	return true
+// Stop will cooperatively stop all remaining tasks, including the tasks
+// of any stream this stream was derived from.
+func (s *StreamInstance) Stop() {
+	s.quitOnce.Do(func() { close(s.quit) })
+	if s.parent != nil {
+		s.parent.Stop()
+		return
+	}
+	s.stopTasks()
 }
 
 // Result is an alias for the completed task's Result method.
@@ -0,0 +1,196 @@
+package await
+
+import (
+	"time"
+
+	"github.com/brad-jones/goasync/v2/clock"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/brad-jones/goerr/v2"
+)
+
+// Map returns a new stream that transforms the result of each resolved task.
+// Rejected tasks are passed through untouched.
+func (s *StreamInstance) Map(fn func(v interface{}) (interface{}, error)) *StreamInstance {
+	return s.pipe(func(in <-chan *task.Task, quit <-chan struct{}, emit func(*task.Task) bool) {
+		for t, ok := receive(in, quit); ok; t, ok = receive(in, quit) {
+			v, err := t.Result()
+			if err == nil {
+				t = settledTask(fn(v))
+			}
+			if !emit(t) {
+				return
+			}
+		}
+	})
+}
+
+// Filter returns a new stream that only contains the tasks for which fn returns true.
+func (s *StreamInstance) Filter(fn func(v interface{}, err error) bool) *StreamInstance {
+	return s.pipe(func(in <-chan *task.Task, quit <-chan struct{}, emit func(*task.Task) bool) {
+		for t, ok := receive(in, quit); ok; t, ok = receive(in, quit) {
+			if fn(t.Result()) && !emit(t) {
+				return
+			}
+		}
+	})
+}
+
+// Batch returns a new stream that groups tasks into batches of the given
+// size, the final batch may be smaller. Each batch is a task that resolves
+// the values of the batched tasks, in the order they finished, or rejects
+// with an ErrTaskFailed if any of the batched tasks rejected.
+func (s *StreamInstance) Batch(size int) *StreamInstance {
+	return s.BatchWithTimeout(size, 0)
+}
+
+// BatchWithTimeout does the same as Batch but will also emit a smaller
+// batch once timeout has passed since the first task in the batch was
+// received. A size of zero or less means batches are only limited by time.
+func (s *StreamInstance) BatchWithTimeout(size int, timeout time.Duration) *StreamInstance {
+	return s.pipe(func(in <-chan *task.Task, quit <-chan struct{}, emit func(*task.Task) bool) {
+		batch := []*task.Task{}
+		var deadline <-chan time.Time
+
+		flush := func() bool {
+			deadline = nil
+			if len(batch) == 0 {
+				return true
+			}
+			t := settledTask(All(batch...))
+			batch = []*task.Task{}
+			return emit(t)
+		}
+
+		for {
+			select {
+			case t, ok := <-in:
+				if !ok {
+					flush()
+					return
+				}
+				batch = append(batch, t)
+				if len(batch) == 1 && timeout > 0 {
+					deadline = clock.Default().After(timeout)
+				}
+				if size > 0 && len(batch) >= size && !flush() {
+					return
+				}
+			case <-deadline:
+				if !flush() {
+					return
+				}
+			case <-quit:
+				return
+			}
+		}
+	})
+}
+
+// Window returns a new stream of sliding windows over the last size tasks.
+// Once size tasks have been received a window is emitted for every task
+// received, each window is a task that resolves the values of the tasks in
+// the window, in the order they finished, or rejects with an ErrTaskFailed
+// if any of the tasks in the window rejected. A size of less than one is
+// treated as one.
+func (s *StreamInstance) Window(size int) *StreamInstance {
+	if size < 1 {
+		size = 1
+	}
+	return s.pipe(func(in <-chan *task.Task, quit <-chan struct{}, emit func(*task.Task) bool) {
+		window := []*task.Task{}
+		for t, ok := receive(in, quit); ok; t, ok = receive(in, quit) {
+			window = append(window, t)
+			if len(window) > size {
+				window = window[1:]
+			}
+			if len(window) == size && !emit(settledTask(All(window...))) {
+				return
+			}
+		}
+	})
+}
+
+// Take returns a new stream that ends after n tasks,
+// all remaining tasks are then stopped.
+func (s *StreamInstance) Take(n int) *StreamInstance {
+	return s.pipe(func(in <-chan *task.Task, quit <-chan struct{}, emit func(*task.Task) bool) {
+		if n <= 0 {
+			s.Stop()
+			return
+		}
+		taken := 0
+		for t, ok := receive(in, quit); ok; t, ok = receive(in, quit) {
+			if !emit(t) {
+				return
+			}
+			taken++
+			if taken == n {
+				s.Stop()
+				return
+			}
+		}
+	})
+}
+
+// Skip returns a new stream that ignores the first n tasks.
+func (s *StreamInstance) Skip(n int) *StreamInstance {
+	return s.pipe(func(in <-chan *task.Task, quit <-chan struct{}, emit func(*task.Task) bool) {
+		skipped := 0
+		for t, ok := receive(in, quit); ok; t, ok = receive(in, quit) {
+			if skipped < n {
+				skipped++
+				continue
+			}
+			if !emit(t) {
+				return
+			}
+		}
+	})
+}
+
+// pipe creates a new stream derived from this stream, fn receives the tasks
+// of this stream from in & emits the tasks of the new stream with emit.
+// Quit is closed & emit returns false once the new stream has been stopped.
+func (s *StreamInstance) pipe(fn func(in <-chan *task.Task, quit <-chan struct{}, emit func(*task.Task) bool)) *StreamInstance {
+	out := make(chan *task.Task)
+	derived := &StreamInstance{
+		completed: out,
+		quit:      make(chan struct{}),
+		parent:    s,
+	}
+	go func() {
+		defer close(out)
+		fn(s.completed, derived.quit, func(t *task.Task) bool {
+			select {
+			case out <- t:
+				return true
+			case <-derived.quit:
+				return false
+			}
+		})
+	}()
+	return derived
+}
+
+// receive returns the next task from in, unless in is closed or quit is closed
+func receive(in <-chan *task.Task, quit <-chan struct{}) (*task.Task, bool) {
+	select {
+	case <-quit:
+		return nil, false
+	default:
+	}
+	select {
+	case t, ok := <-in:
+		return t, ok
+	case <-quit:
+		return nil, false
+	}
+}
+
+// settledTask returns a task that has already rejected err or resolved v
+func settledTask(v interface{}, err error) *task.Task {
+	if err != nil {
+		return task.Rejected(goerr.Wrap(err))
+	}
+	return task.Resolved(v)
+}
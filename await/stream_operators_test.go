@@ -0,0 +1,165 @@
+package await_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/await"
+	"github.com/brad-jones/goasync/v2/clock"
+	"github.com/brad-jones/goasync/v2/clock/fake"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/stretchr/testify/assert"
+)
+
+// ints returns an ordered stream of tasks that resolve the given values
+func ints(values ...int) *await.StreamInstance {
+	tasks := []*task.Task{}
+	for _, v := range values {
+		tasks = append(tasks, task.Resolved(v))
+	}
+	return await.OrderedStream(tasks...)
+}
+
+// blocked returns a task that only returns once it is told to stop
+func blocked() *task.Task {
+	return task.New(func(t *task.Internal) {
+		<-*t.Stopper
+	})
+}
+
+// collect returns the values & errors of every task in the stream
+func collect(s *await.StreamInstance) (values []interface{}, errs []error) {
+	for v, err := range s.All() {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		values = append(values, v)
+	}
+	return values, errs
+}
+
+// receiveTask returns the next task of the stream, failing the test if it takes too long
+func receiveTask(t *testing.T, s *await.StreamInstance) *task.Task {
+	t.Helper()
+	select {
+	case v, ok := <-s.C():
+		if !ok {
+			t.Fatal("stream ended early")
+		}
+		return v
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the stream")
+		return nil
+	}
+}
+
+func TestStreamMap(t *testing.T) {
+	errBoom := errors.New("boom")
+	s := await.OrderedStream(task.Resolved(1), task.Rejected(errBoom), task.Resolved(2), task.Resolved(3))
+	values, errs := collect(s.Map(func(v interface{}) (interface{}, error) {
+		if v == 3 {
+			return nil, errors.New("three")
+		}
+		return v.(int) * 10, nil
+	}))
+	assert.Equal(t, []interface{}{10, 20}, values)
+	if assert.Len(t, errs, 2) {
+		assert.ErrorIs(t, errs[0], errBoom)
+		assert.EqualError(t, errs[1], "three")
+	}
+}
+
+func TestStreamFilter(t *testing.T) {
+	values, _ := collect(ints(1, 2, 3, 4).Filter(func(v interface{}, err error) bool {
+		return v.(int)%2 == 0
+	}))
+	assert.Equal(t, []interface{}{2, 4}, values)
+}
+
+func TestStreamBatch(t *testing.T) {
+	values, errs := collect(ints(1, 2, 3, 4, 5).Batch(2))
+	assert.Empty(t, errs)
+	assert.Equal(t, []interface{}{
+		[]interface{}{1, 2},
+		[]interface{}{3, 4},
+		[]interface{}{5},
+	}, values)
+}
+
+func TestStreamBatchRejected(t *testing.T) {
+	s := await.OrderedStream(task.Resolved(1), task.Rejected(errors.New("boom")), task.Resolved(3))
+	values, errs := collect(s.Batch(2))
+	assert.Equal(t, []interface{}{[]interface{}{3}}, values)
+	if assert.Len(t, errs, 1) {
+		assert.ErrorAs(t, errs[0], new(*await.ErrTaskFailed))
+	}
+}
+
+func TestStreamBatchWithTimeout(t *testing.T) {
+	c := fake.New(time.Now())
+	defer clock.SetDefault(clock.SetDefault(c))
+
+	a, b, d := task.NewSource(), task.NewSource(), task.NewSource()
+	s := await.OrderedStream(a.Task(), b.Task(), d.Task()).BatchWithTimeout(10, time.Second)
+
+	// The first batch is emitted once the timeout passes, even though it is not full
+	a.Resolve(1)
+	c.BlockUntil(1)
+	c.Advance(time.Second)
+	assert.Equal(t, []interface{}{1}, receiveTask(t, s).MustResult())
+
+	// The last batch is emitted once the stream ends
+	b.Resolve(2)
+	d.Resolve(3)
+	assert.Equal(t, []interface{}{2, 3}, receiveTask(t, s).MustResult())
+	_, ok := <-s.C()
+	assert.False(t, ok)
+}
+
+func TestStreamWindow(t *testing.T) {
+	values, _ := collect(ints(1, 2, 3, 4).Window(2))
+	assert.Equal(t, []interface{}{
+		[]interface{}{1, 2},
+		[]interface{}{2, 3},
+		[]interface{}{3, 4},
+	}, values)
+}
+
+func TestStreamWindowSizeLessThanOne(t *testing.T) {
+	for _, size := range []int{0, -1} {
+		values, _ := collect(ints(1, 2).Window(size))
+		assert.Equal(t, []interface{}{[]interface{}{1}, []interface{}{2}}, values)
+	}
+}
+
+func TestStreamTake(t *testing.T) {
+	a, b := blocked(), blocked()
+	values, _ := collect(await.OrderedStream(task.Resolved(1), task.Resolved(2), a, b).Take(2))
+	assert.Equal(t, []interface{}{1, 2}, values)
+
+	// The upstream tasks that were not taken are stopped
+	assert.Equal(t, task.StateStopped, a.State())
+	assert.Equal(t, task.StateStopped, b.State())
+}
+
+func TestStreamTakeNone(t *testing.T) {
+	a := blocked()
+	values, _ := collect(await.Stream(a).Take(0))
+	assert.Empty(t, values)
+	assert.Equal(t, task.StateStopped, a.State())
+}
+
+func TestStreamSkip(t *testing.T) {
+	values, _ := collect(ints(1, 2, 3, 4).Skip(2))
+	assert.Equal(t, []interface{}{3, 4}, values)
+}
+
+func TestStreamChained(t *testing.T) {
+	values, _ := collect(ints(1, 2, 3, 4, 5, 6).
+		Filter(func(v interface{}, err error) bool { return v.(int)%2 == 0 }).
+		Map(func(v interface{}) (interface{}, error) { return v.(int) * 10, nil }).
+		Take(2))
+	assert.Equal(t, []interface{}{20, 40}, values)
+}
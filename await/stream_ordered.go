@@ -0,0 +1,106 @@
+package await
+
+import (
+	"sync"
+
+	"github.com/brad-jones/goasync/v2/stop"
+	"github.com/brad-jones/goasync/v2/task"
+)
+
+// OrderedStream does the same as Stream but tasks are received in the same
+// order as the input rather than the order they finish. Tasks that finish
+// out of order are held back until all the tasks before them have finished.
+//
+// For example:
+// 	for r, err := range await.OrderedStream(page1(), page2(), page3()).All() {
+// 	}
+func OrderedStream(awaitables ...*task.Task) *StreamInstance {
+	factories := []func() *task.Task{}
+	for _, v := range awaitables {
+		awaitable := v
+		factories = append(factories, func() *task.Task { return awaitable })
+	}
+	return OrderedStreamWithBuffer(len(awaitables), factories...)
+}
+
+// OrderedStreamWithBuffer does the same as OrderedStream but creates the tasks
+// lazily by calling the given factories in order. At most maxBuffer tasks will
+// exist that have not yet been received, once that limit is reached no more
+// tasks are created until the oldest task has been received.
+//
+// For example, download at most 10 pages at a time but process them in order:
+// 	factories := []func() *task.Task{}
+// 	for i := 1; i <= 1000; i++ {
+// 		page := i
+// 		factories = append(factories, func() *task.Task { return downloadAsync(page) })
+// 	}
+// 	for r, err := range await.OrderedStreamWithBuffer(10, factories...).All() {
+// 	}
+func OrderedStreamWithBuffer(maxBuffer int, factories ...func() *task.Task) *StreamInstance {
+	if maxBuffer < 1 {
+		maxBuffer = 1
+	}
+
+	out := make(chan *task.Task)
+	s := &StreamInstance{
+		completed: out,
+		quit:      make(chan struct{}),
+	}
+
+	// Protects started from being appended to while we stop the tasks
+	mu := sync.Mutex{}
+	started := []*task.Task{}
+
+	s.stopTasks = func() {
+		mu.Lock()
+		tasks := started
+		mu.Unlock()
+		stop.All(tasks...)
+	}
+
+	start := func(i int) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		select {
+		case <-s.quit:
+			return false
+		default:
+		}
+		started = append(started, factories[i]())
+		return true
+	}
+
+	go func() {
+		defer close(out)
+
+		for i := 0; i < maxBuffer && i < len(factories); i++ {
+			if !start(i) {
+				return
+			}
+		}
+
+		for i := range factories {
+			mu.Lock()
+			t := started[i]
+			mu.Unlock()
+
+			select {
+			case <-*t.Done:
+			case <-s.quit:
+				return
+			}
+
+			select {
+			case out <- t:
+			case <-s.quit:
+				return
+			}
+
+			if next := i + maxBuffer; next < len(factories) && !start(next) {
+				return
+			}
+		}
+	}()
+
+	return s
+}
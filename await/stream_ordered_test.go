@@ -0,0 +1,102 @@
+package await_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/await"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/stretchr/testify/assert"
+)
+
+// nothingReceived asserts the stream does not emit a task for a little while
+func nothingReceived(t *testing.T, s *await.StreamInstance) {
+	t.Helper()
+	select {
+	case <-s.C():
+		t.Fatal("received a task out of order")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestOrderedStream(t *testing.T) {
+	a, b, c := task.NewSource(), task.NewSource(), task.NewSource()
+	s := await.OrderedStream(a.Task(), b.Task(), c.Task())
+
+	// Tasks that finish early are held back until the tasks before them finish
+	c.Resolve("c")
+	b.Resolve("b")
+	nothingReceived(t, s)
+
+	a.Resolve("a")
+	for _, expected := range []string{"a", "b", "c"} {
+		assert.Equal(t, expected, receiveTask(t, s).MustResult())
+	}
+	_, ok := <-s.C()
+	assert.False(t, ok)
+}
+
+func TestOrderedStreamWithBuffer(t *testing.T) {
+	created := make(chan *task.Source, 10)
+	factories := []func() *task.Task{}
+	for i := 0; i < 4; i++ {
+		factories = append(factories, func() *task.Task {
+			src := task.NewSource()
+			created <- src
+			return src.Task()
+		})
+	}
+
+	next := func() *task.Source {
+		t.Helper()
+		select {
+		case src := <-created:
+			return src
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for a task to be created")
+			return nil
+		}
+	}
+	idle := func() {
+		t.Helper()
+		select {
+		case <-created:
+			t.Fatal("created more tasks than the buffer allows")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	s := await.OrderedStreamWithBuffer(2, factories...)
+	first, second := next(), next()
+	idle()
+
+	// Finishing a task does not make room, it must be received first
+	second.Resolve(2)
+	first.Resolve(1)
+	idle()
+
+	assert.Equal(t, 1, receiveTask(t, s).MustResult())
+	third := next()
+	idle()
+
+	assert.Equal(t, 2, receiveTask(t, s).MustResult())
+	fourth := next()
+
+	fourth.Resolve(4)
+	third.Resolve(3)
+	assert.Equal(t, 3, receiveTask(t, s).MustResult())
+	assert.Equal(t, 4, receiveTask(t, s).MustResult())
+	_, ok := <-s.C()
+	assert.False(t, ok)
+}
+
+func TestOrderedStreamStop(t *testing.T) {
+	a, b := blocked(), blocked()
+	s := await.OrderedStream(task.Resolved(1), a, b)
+	for v := range s.All() {
+		assert.Equal(t, 1, v)
+		break
+	}
+	assert.Equal(t, task.StateStopped, a.State())
+	assert.Equal(t, task.StateStopped, b.State())
+}
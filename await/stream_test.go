@@ -0,0 +1,173 @@
+package await
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/stretchr/testify/assert"
+)
+
+// legacyStream is the original implementation of StreamInstance.Wait which
+// spawned a goroutine for every remaining task on every call, it is kept
+// here so the benchmark can show the difference.
+type legacyStream struct {
+	awaitables []*task.Task
+	current    *task.Task
+}
+
+func (s *legacyStream) Wait() bool {
+	if len(s.awaitables) == 0 {
+		return false
+	}
+
+	doneCh := make(chan struct{}, 1)
+	awaitableCh := make(chan *task.Task, 1)
+	for _, v := range s.awaitables {
+		awaitable := v
+		go func() {
+			select {
+			case <-*awaitable.Done:
+				// The original closed awaitableCh here, which panics
+				// when two tasks finish at the same time.
+				select {
+				case awaitableCh <- awaitable:
+				default:
+				}
+			case <-doneCh:
+				return
+			}
+		}()
+	}
+	s.current = <-awaitableCh
+	close(doneCh)
+
+	newAwaitables := []*task.Task{}
+	for _, v := range s.awaitables {
+		if v != s.current {
+			newAwaitables = append(newAwaitables, v)
+		}
+	}
+	s.awaitables = newAwaitables
+
+	return true
+}
+
+// gatedTasks returns n tasks that finish one at a time, each time release is called.
+func gatedTasks(n int) (tasks []*task.Task, release func()) {
+	gates := make([]chan struct{}, n)
+	for i := range gates {
+		gate := make(chan struct{})
+		gates[i] = gate
+		tasks = append(tasks, task.New(func(t *task.Internal) {
+			<-gate
+			t.Resolve(1)
+		}))
+	}
+	next := 0
+	return tasks, func() {
+		close(gates[next])
+		next++
+	}
+}
+
+// blocked returns a task that only returns once it is told to stop
+func blocked() *task.Task {
+	return task.New(func(t *task.Internal) {
+		<-*t.Stopper
+	})
+}
+
+func TestStreamWait(t *testing.T) {
+	tasks, release := gatedTasks(3)
+	s := Stream(tasks...)
+	for i := 0; i < 3; i++ {
+		release()
+		assert.True(t, s.Wait())
+		assert.Same(t, tasks[i], s.Task())
+		assert.Equal(t, 1, s.MustResult())
+	}
+	assert.False(t, s.Wait())
+}
+
+func TestStreamEmpty(t *testing.T) {
+	assert.False(t, Stream().Wait())
+	_, ok := <-Stream().C()
+	assert.False(t, ok)
+}
+
+func TestStreamC(t *testing.T) {
+	tasks, release := gatedTasks(3)
+	c := Stream(tasks...).C()
+	for i := 0; i < 3; i++ {
+		release()
+		select {
+		case v := <-c:
+			assert.Same(t, tasks[i], v)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for a task")
+		}
+	}
+	_, ok := <-c
+	assert.False(t, ok)
+}
+
+func TestStreamAll(t *testing.T) {
+	sum := 0
+	errs := 0
+	for v, err := range Stream(task.Resolved(1), task.Rejected(errors.New("boom")), task.Resolved(2)).All() {
+		if err != nil {
+			errs++
+			continue
+		}
+		sum += v.(int)
+	}
+	assert.Equal(t, 3, sum)
+	assert.Equal(t, 1, errs)
+}
+
+func TestStreamAllBreak(t *testing.T) {
+	a, b := blocked(), blocked()
+	for v := range Stream(task.Resolved(1), a, b).All() {
+		assert.Equal(t, 1, v)
+		break
+	}
+	assert.Equal(t, task.StateStopped, a.State())
+	assert.Equal(t, task.StateStopped, b.State())
+}
+
+// BenchmarkStream compares the legacy implementation, which creates O(n²)
+// goroutines over the life of a stream, with the channel based implementation
+// which creates O(n). The difference shows up in both ns/op and allocs/op.
+func BenchmarkStream(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run("legacy/"+strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				tasks, release := gatedTasks(n)
+				s := &legacyStream{awaitables: tasks}
+				release()
+				for count := 1; s.Wait(); count++ {
+					if count < n {
+						release()
+					}
+				}
+			}
+		})
+		b.Run("channel/"+strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				tasks, release := gatedTasks(n)
+				s := Stream(tasks...)
+				release()
+				for count := 1; s.Wait(); count++ {
+					if count < n {
+						release()
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Package clock abstracts the passing of time so that
+// timeouts & schedules can be tested deterministically.
+package clock
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Clock tells the time & waits for time to pass.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+
+	// Since returns the time elapsed since t
+	Since(t time.Time) time.Duration
+
+	// Until returns the duration until t
+	Until(t time.Time) time.Duration
+
+	// After waits for the duration to elapse & then sends the current time on the returned channel
+	After(d time.Duration) <-chan time.Time
+
+	// Sleep pauses the current goroutine for at least the duration d
+	Sleep(d time.Duration)
+
+	// NewTimer creates a new Timer that will send the current time on it's channel after at least duration d
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is the equivalent of a time.Timer
+type Timer interface {
+	// C returns the channel on which the time is delivered
+	C() <-chan time.Time
+
+	// Stop prevents the Timer from firing, returns false if the timer has already expired or been stopped
+	Stop() bool
+
+	// Reset changes the timer to expire after duration d, returns true if the timer had been active
+	Reset(d time.Duration) bool
+}
+
+// holder lets an atomic.Value always store the same concrete type
+type holder struct {
+	clock Clock
+}
+
+var defaultClock atomic.Value
+
+func init() {
+	defaultClock.Store(holder{Real()})
+}
+
+// Default returns the clock used by this module when one is not given explicitly,
+// this is the real clock unless it has been replaced with SetDefault.
+func Default() Clock {
+	return defaultClock.Load().(holder).clock
+}
+
+// SetDefault replaces the default clock, eg: with a fake clock in a test.
+// A nil clock restores the real clock. The previous default is returned
+// so it can be restored, eg: `defer clock.SetDefault(clock.SetDefault(c))`
+func SetDefault(c Clock) Clock {
+	if c == nil {
+		c = Real()
+	}
+	previous := Default()
+	defaultClock.Store(holder{c})
+	return previous
+}
+
+// Or returns c unless it is nil, in which case the default clock is returned.
+// Useful for options that accept an optional clock.
+func Or(c Clock) Clock {
+	if c == nil {
+		return Default()
+	}
+	return c
+}
+
+// Real returns a clock that uses the time package.
+func Real() Clock {
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (realClock) Until(t time.Time) time.Duration        { return time.Until(t) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (realClock) NewTimer(d time.Duration) Timer         { return &realTimer{time.NewTimer(d)} }
+
+type realTimer struct {
+	timer *time.Timer
+}
+
+func (t *realTimer) C() <-chan time.Time        { return t.timer.C }
+func (t *realTimer) Stop() bool                 { return t.timer.Stop() }
+func (t *realTimer) Reset(d time.Duration) bool { return t.timer.Reset(d) }
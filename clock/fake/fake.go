@@ -0,0 +1,171 @@
+// Package fake provides a clock that only moves when told to.
+package fake
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/clock"
+)
+
+// Clock is a clock.Clock whose time only changes when Advance or Set is
+// called, create new instances with New. Timers, After & Sleep fire as soon
+// as the clock is moved to or past their deadline.
+//
+// For example:
+// 	c := fake.New(time.Now())
+// 	defer clock.SetDefault(clock.SetDefault(c))
+// 	go func() { c.BlockUntil(1); c.Advance(5 * time.Second) }()
+// 	err := t.StopWithTimeout(5 * time.Second) // returns without waiting 5 real seconds
+type Clock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*timer
+}
+
+// New creates a fake clock set to the given time.
+func New(now time.Time) *Clock {
+	c := &Clock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the current fake time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Since returns the fake time elapsed since t.
+func (c *Clock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// Until returns the fake duration until t.
+func (c *Clock) Until(t time.Time) time.Duration {
+	return t.Sub(c.Now())
+}
+
+// After waits for the clock to be advanced by the duration & then sends the fake time on the returned channel.
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+// Sleep blocks until the clock has been advanced by the duration.
+func (c *Clock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+// NewTimer creates a timer that fires once the clock has been advanced by the duration.
+func (c *Clock) NewTimer(d time.Duration) clock.Timer {
+	t := &timer{clock: c, ch: make(chan time.Time, 1)}
+	t.Reset(d)
+	return t
+}
+
+// Advance moves the clock forward by the duration, firing any timers that are due in order.
+func (c *Clock) Advance(d time.Duration) {
+	c.Set(c.Now().Add(d))
+}
+
+// Set moves the clock to the given time, firing any timers that are due in order.
+func (c *Clock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sort.SliceStable(c.waiters, func(i, j int) bool {
+		return c.waiters[i].deadline.Before(c.waiters[j].deadline)
+	})
+
+	remaining := []*timer{}
+	for _, t := range c.waiters {
+		if t.deadline.After(now) {
+			remaining = append(remaining, t)
+			continue
+		}
+		t.active = false
+		select {
+		case t.ch <- t.deadline:
+		default:
+		}
+	}
+	c.waiters = remaining
+	c.now = now
+	c.cond.Broadcast()
+}
+
+// Waiters returns the number of timers, including After & Sleep calls,
+// that are waiting for the clock to be advanced.
+func (c *Clock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// BlockUntil blocks until at least n timers are waiting for the clock to be
+// advanced. Use this to make sure the code under test has started waiting
+// before calling Advance.
+func (c *Clock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
+
+type timer struct {
+	clock    *Clock
+	ch       chan time.Time
+	deadline time.Time
+	active   bool
+}
+
+func (t *timer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *timer) Stop() bool {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.remove(t)
+}
+
+func (t *timer) Reset(d time.Duration) bool {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	wasActive := c.remove(t)
+	t.deadline = c.now.Add(d)
+	if d <= 0 {
+		select {
+		case t.ch <- c.now:
+		default:
+		}
+		return wasActive
+	}
+
+	t.active = true
+	c.waiters = append(c.waiters, t)
+	c.cond.Broadcast()
+	return wasActive
+}
+
+// remove stops waiting on the timer, the caller must hold mu
+func (c *Clock) remove(t *timer) bool {
+	if !t.active {
+		return false
+	}
+	t.active = false
+	for i, w := range c.waiters {
+		if w == t {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			break
+		}
+	}
+	return true
+}
@@ -0,0 +1,173 @@
+package fake_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/await"
+	"github.com/brad-jones/goasync/v2/clock"
+	"github.com/brad-jones/goasync/v2/clock/fake"
+	"github.com/brad-jones/goasync/v2/retry"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/stretchr/testify/assert"
+)
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// stuck returns a running task that ignores being told to stop until release is closed
+func stuck(release chan struct{}) *task.Task {
+	running := make(chan struct{})
+	t := task.New(func() {
+		close(running)
+		<-release
+	})
+	<-running
+	return t
+}
+
+// eventually returns the value sent to ch, failing the test if nothing is sent within a second
+func eventually[T any](t *testing.T, ch chan T) T {
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(time.Second):
+		t.Fatal("nothing was received")
+		panic("unreachable")
+	}
+}
+
+func TestTimers(t *testing.T) {
+	c := fake.New(epoch)
+	a := c.NewTimer(2 * time.Second)
+	b := c.NewTimer(time.Second)
+	stopped := c.NewTimer(time.Second)
+	assert.Equal(t, 3, c.Waiters())
+	assert.True(t, stopped.Stop())
+	assert.False(t, stopped.Stop())
+
+	c.Advance(time.Second)
+	assert.Equal(t, epoch.Add(time.Second), <-b.C())
+	assert.Equal(t, 1, c.Waiters())
+	assert.Len(t, a.C(), 0)
+	assert.Len(t, stopped.C(), 0)
+
+	assert.True(t, a.Reset(time.Second))
+	c.Advance(time.Second)
+	assert.Equal(t, epoch.Add(2*time.Second), <-a.C())
+	assert.Equal(t, 0, c.Waiters())
+	assert.Equal(t, 2*time.Second, c.Since(epoch))
+	assert.Equal(t, time.Second, c.Until(epoch.Add(3*time.Second)))
+}
+
+func TestSleep(t *testing.T) {
+	c := fake.New(epoch)
+	woke := make(chan time.Time)
+	go func() {
+		c.Sleep(time.Minute)
+		woke <- c.Now()
+	}()
+
+	c.BlockUntil(1)
+	c.Set(epoch.Add(time.Hour))
+	assert.Equal(t, epoch.Add(time.Hour), eventually(t, woke))
+}
+
+func TestStopWithTimeout(t *testing.T) {
+	c := fake.New(epoch)
+	defer clock.SetDefault(clock.SetDefault(c))
+	release := make(chan struct{})
+	defer close(release)
+
+	errs := make(chan error)
+	s := stuck(release)
+	go func() { errs <- s.StopWithTimeout(time.Hour) }()
+	c.BlockUntil(1)
+	c.Advance(time.Hour)
+	var timeout *task.ErrStoppingTaskTimeout
+	assert.ErrorAs(t, eventually(t, errs), &timeout)
+	assert.Equal(t, 0, c.Waiters())
+
+	// The timer is stopped when the task stops in time
+	quick := task.New(func(t *task.Internal) { <-*t.Stopper })
+	assert.NoError(t, quick.StopWithTimeout(time.Hour))
+	assert.Equal(t, 0, c.Waiters())
+}
+
+func TestResultWithTimeout(t *testing.T) {
+	c := fake.New(epoch)
+	defer clock.SetDefault(clock.SetDefault(c))
+
+	errs := make(chan error)
+	slow := task.New(func(t *task.Internal) { <-*t.Stopper })
+	go func() {
+		_, err := slow.ResultWithTimeout(time.Minute, time.Minute)
+		errs <- err
+	}()
+	c.BlockUntil(1)
+	c.Advance(time.Minute)
+
+	var timedOut *task.ErrTaskTimedOut
+	assert.ErrorAs(t, eventually(t, errs), &timedOut)
+	assert.Equal(t, task.StateTimedOut, slow.State())
+	at, ok := slow.EnteredAt(task.StateTimedOut)
+	assert.True(t, ok)
+	assert.Equal(t, c.Now(), at)
+}
+
+func TestAwaitWithTimeout(t *testing.T) {
+	c := fake.New(epoch)
+	defer clock.SetDefault(clock.SetDefault(c))
+	release := make(chan struct{})
+	defer close(release)
+
+	// Once a task resolves the others are given a minute to stop
+	values := make(chan interface{})
+	go func() {
+		v, err := await.AnySuccessWithTimeout(time.Minute, task.Rejected(errors.New("boom")), task.Resolved(1), stuck(release))
+		assert.NoError(t, err)
+		values <- v
+	}()
+	c.BlockUntil(1)
+	c.Advance(time.Minute)
+	assert.Equal(t, 1, eventually(t, values))
+
+	outcomes := make(chan []*await.Outcome)
+	go func() {
+		o, err := await.SomeWithTimeout(time.Minute, 1, stuck(release), task.Resolved("a"))
+		assert.NoError(t, err)
+		outcomes <- o
+	}()
+	c.BlockUntil(1)
+	c.Advance(time.Minute)
+	o := eventually(t, outcomes)
+	if assert.Len(t, o, 1) {
+		assert.Equal(t, 1, o[0].Index)
+		assert.Equal(t, "a", o[0].Value)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	c := fake.New(epoch)
+	attempts := 0
+	r := retry.DoAsync(retry.Options{
+		Backoff:    retry.Exponential(time.Minute, time.Hour, 2),
+		MaxElapsed: 10 * time.Minute,
+		Clock:      c,
+	}, func() *task.Task {
+		attempts++
+		return task.Rejected(errors.New("boom"))
+	})
+
+	// Attempts are made after 0, 1, 3 & 7 minutes, the next would be after 15
+	for _, d := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute} {
+		c.BlockUntil(1)
+		c.Advance(d)
+	}
+
+	var failed *retry.ErrAttemptsFailed
+	assert.ErrorAs(t, r.Wait(), &failed)
+	assert.Equal(t, 4, attempts)
+	assert.Len(t, failed.Errors, 4)
+	assert.Equal(t, 7*time.Minute, c.Since(epoch))
+}
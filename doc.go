@@ -319,6 +319,94 @@ Here is an example of `fooAsync` but this time it uses https://github.com/brad-j
 	v, err := t.Result()
 	castedV := v.(string)
 
+Task Sources
+
+Sometimes a task needs to be resolved from the outside, for example by a
+callback. A Source provides a task along with Resolve, Reject & Cancel methods.
+
+	s := task.NewSource()
+	ws.OnMessage(func(msg string) { s.Resolve(msg) })
+	v, err := s.Task().Result()
+
+Generators
+
+A task can only resolve a single value, a Generator on the other hand can yield
+many values over time. Yield blocks until the value has been received & returns
+false once the generator has been stopped.
+
+	g := task.NewGenerator(func(t *task.Internal, yield func(v interface{}) bool) {
+		for line := range tail(file) {
+			if !yield(line) {
+				return
+			}
+		}
+	})
+	for line, err := range g.All() {
+	}
+
+Scheduling
+
+After executes a function once a duration has passed, Every & Cron execute a
+function periodically. Each run is a task & the outcome of each run can be
+received as it finishes, stopping a schedule also stops the current run.
+
+	s := task.Every(time.Minute, pollAsync, task.ScheduleOptions{Jitter: 5 * time.Second})
+	s, err := task.Cron("0 9 * * MON-FRI", reportAsync, task.ScheduleOptions{})
+	for value, err := range s.All() {
+	}
+
+Combinators
+
+Much like JS Promises, tasks can be composed with Then, Catch, Finally, Map
+and FlatMap. Each returns a new task that shares the stopper of the original.
+
+	t := fooAsync(bar).
+		Map(func(v interface{}) (interface{}, error) { return strconv.Atoi(v.(string)) }).
+		Catch(func(err error) interface{} { return 0 }).
+		Finally(func() { fmt.Println("done") })
+
+Child Tasks
+
+A task may spawn (or adopt) child tasks. Children are told to stop when their
+parent is told to stop and a parent is not done until all of it's children are
+done, so no task can outlive the scope it was created in.
+
+	parent := task.New(func(t *task.Internal) {
+		child1 := t.Spawn(func(t *task.Internal) { ... })
+		child2 := t.Adopt(fooAsync(bar))
+	})
+
+Contexts
+
+Tasks can also be bound to a context, the task is told to stop when the context
+is canceled and any deadline or values are inherited by Internal.CancelableCtx.
+
+	t := task.NewWithContext(r.Context(), func(t *task.Internal) {
+		req, _ := http.NewRequestWithContext(t.CancelableCtx(), "GET", url, nil)
+		...
+	})
+
+Waits can be bound by a context too, without stopping the task.
+
+	v, err := t.ResultCtx(ctx)
+
+Task State
+
+Every task moves through a simple lifecycle which can be inspected at any time.
+
+	switch t.State() {
+	case task.StateResolved:
+	case task.StateRejected:
+	case task.StateStopped:
+	case task.StatePanicked:
+	case task.StateTimedOut:
+	case task.StateCompleted:
+	}
+
+Tasks that are stopped without resolving anything will return an
+ErrTaskStopped from Result and tasks that panic will return an
+ErrTaskPanicked which contains the stack of the panicking goroutine.
+
 The Await API
 
 Tasks can be awaited using https://github.com/brad-jones/goasync/await
@@ -326,6 +414,9 @@ Tasks can be awaited using https://github.com/brad-jones/goasync/await
 	values, errors := await.All(task1, task2, task3)
 	values, error := await.AllOrError(task1, task2, task3)
 	value, error := await.Any(task1, task2, task3)
+	outcomes, error := await.AllSettled(task1, task2, task3)
+	outcomes, error := await.Some(2, task1, task2, task3)
+	value, error := await.AnySuccess(task1, task2, task3)
 
 The awaiters that return early (before all tasks are complete) such as Any will
 cooperatively stop the remaining tasks. So cancelation will happen automatically.
@@ -341,11 +432,142 @@ Or perhaps you might like to use a timeout.
 
 	value, error := await.AnyWithTimeout(5 * time.Second, task1, task2, task3)
 
-Not Type Safe
+To run a function over many items with a limited number of tasks in flight use
+Map or ForEach, results are returned in the same order as the items. The
+`OrError` variants return on the first error & stop all running tasks.
+
+	values, error := await.Map(urls, 16, downloadAsync)
+	error := await.ForEachOrError(files, 4, uploadAsync)
+
+Results can also be streamed as each task finishes.
+
+	for value, error := range await.Stream(task1, task2, task3).All() {
+	}
+
+Streams can be transformed with Map, Filter, Batch, Window, Take & Skip.
+Operators that end a stream early, such as Take, stop the remaining tasks.
+
+	s := await.Stream(tasks...).Filter(isValid).Batch(10).Take(2)
+
+If results are needed in the same order as the tasks were given use an ordered
+stream. OrderedStreamWithBuffer creates tasks lazily from factories & will not
+create more tasks while the given number of results are waiting to be received.
+
+	for value, error := range await.OrderedStreamWithBuffer(10, factories...).All() {
+	}
+
+Rate Limiting
+
+To stay within an api's quota use a token bucket or leaky bucket from
+https://github.com/brad-jones/goasync/limiter, tasks created with Go stay
+pending until the limiter allows them to start. One limiter can be shared
+between many pools, await.Map calls, etc.
+
+	l := limiter.NewTokenBucket(limiter.PerSecond(10), 5)
+	values, error := await.Map(ids, 16, func(id string) *task.Task {
+		return l.Go(func(t *task.Internal) { t.Settle(fetch(id)) })
+	})
+
+Hedging
+
+To cut the tail latency of a backend that is sometimes slow, use
+https://github.com/brad-jones/goasync/hedge to start another copy of a task that
+has not resolved after a delay. The first copy to resolve wins & every other copy
+is stopped. Given a Percentile the delay is learned from previous latencies.
+
+	h := hedge.New(hedge.Options{Delay: 100 * time.Millisecond, Percentile: 0.95})
+	value, error := h.Do(func() *task.Task { return fetchAsync(id) })
+
+Dependency Graphs
+
+When tasks depend on the results of other tasks, like the steps of a build, use
+https://github.com/brad-jones/goasync/graph to declare the dependencies by name.
+Each node starts as soon as it's dependencies resolve & a failed node causes
+every node that depends on it to be skipped.
+
+	g := graph.New()
+	g.Add("compile", nil, compileAsync)
+	g.Add("test", []string{"compile"}, testAsync)
+	results, error := g.Run(graph.Options{MaxParallel: 4})
+
+Stopping Many Tasks
+
+The https://github.com/brad-jones/goasync/stop package stops many tasks in bulk.
+The `Concurrently` variants signal every task at once & a timeout is an overall
+deadline, any tasks that did not stop in time are listed by an ErrStopFailed.
+
+	err := stop.AllConcurrentlyWithTimeout(5 * time.Second, tasks...)
+
+Graceful Shutdown
+
+Long running tasks can be registered with a shutdown manager from
+https://github.com/brad-jones/goasync/shutdown which waits for SIGINT or
+SIGTERM & then stops each phase in the reverse order it was registered.
+A second signal stops waiting for the remaining tasks.
+
+	m := shutdown.New(shutdown.Options{Timeout: 5 * time.Second})
+	m.Register("db", "postgres", dbAsync())
+	m.Register("http", "api", serveAsync())
+	err := m.Wait()
+
+Supervisors
+
+Long running tasks that should be restarted when they reject or panic can be
+supervised with https://github.com/brad-jones/goasync/supervisor using Erlang
+style OneForOne, OneForAll & RestForOne strategies. A supervisor that restarts
+it's children too often rejects, escalating to any parent supervisor.
+
+	s := supervisor.New(supervisor.Options{Strategy: supervisor.OneForOne},
+		&supervisor.Child{Name: "consumer", Start: consumeAsync},
+	)
+	m.Register("workers", "supervisor", s.Task)
+
+Testing Time
+
+Timeouts, schedules, retries & the like tell the time using the default clock
+from https://github.com/brad-jones/goasync/clock. Tests can replace it with a
+fake clock that only moves when told to, so timeouts can be triggered without
+waiting for real time to pass. Options that accept a Clock may also be given
+a fake clock directly.
+
+	c := fake.New(time.Now())
+	defer clock.SetDefault(clock.SetDefault(c))
+	go func() { c.BlockUntil(1); c.Advance(time.Minute) }()
+	err := t.StopWithTimeout(time.Minute)
+
+Testing Tasks
+
+https://github.com/brad-jones/goasync/asynctest contains assertions for tasks
+that wait for a result without sleeping, a leak checker & fake tasks that
+resolve or reject on command.
+
+	func TestFoo(t *testing.T) {
+		asynctest.CheckLeaks(t)
+		dep := asynctest.NewFake()
+		foo := fooAsync(dep.Task())
+		dep.Reject(errors.New("boom"))
+		asynctest.AssertRejectsWith(t, foo, &await.ErrTaskFailed{}, time.Second)
+	}
+
+Type Safety
+
+The task & await packages use the `interface{}` type, this means that all values
+that are returned from a task's `Result()` method or an awaiter must be casted
+correctly by the caller.
+
+If you would rather have the compiler check this for you then use the generic
+equivalents found in https://github.com/brad-jones/goasync/typed
+
+	func fooAsync() *typed.Task[string] {
+		return typed.New(func(t *typed.Internal[string]) {
+			t.Resolve("foo")
+		})
+	}
+
+	v, err := fooAsync().Result() // v is a string
+
+A typed task embeds a regular task so the two APIs can be mixed freely.
 
-Due to go's lack of generics the only sane why this package can be created is
-by the use of the `interface{}` type. This means that all values that are
-returned from a task's `Result()` method or an awaiter must be casted correctly
-by the caller.
+	stop.All(fooAsync().Task)
 */
 package goasync
@@ -9,8 +9,7 @@ import (
 
 func cancelableAsync() *task.Task {
 	return task.New(func(t *task.Internal) {
-		chainedTask := chainedCancelableAsync()
-		chainedTask.Stopper = t.Stopper
+		chainedTask := t.Adopt(chainedCancelableAsync())
 		for i := 1; i < 10; i++ {
 			if t.ShouldStop() {
 				fmt.Println("cancelableAsync: I stopped cooperatively")
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/task"
+)
+
+func main() {
+	start := time.Now()
+	fmt.Println("START", start)
+
+	pages := task.NewGenerator(func(t *task.Internal, yield func(v interface{}) bool) {
+		for page := 1; ; page++ {
+			select {
+			case <-time.After(1 * time.Second):
+			case <-*t.Stopper:
+			}
+			if !yield(fmt.Sprint("page ", page)) {
+				fmt.Println("generator stopped")
+				return
+			}
+		}
+	})
+
+	for v, err := range pages.All() {
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(v)
+		if v == "page 3" {
+			break
+		}
+	}
+
+	fmt.Println("END", time.Since(start))
+}
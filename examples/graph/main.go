@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/graph"
+	"github.com/brad-jones/goasync/v2/task"
+)
+
+func step(name string) func(inputs map[string]interface{}) *task.Task {
+	return func(inputs map[string]interface{}) *task.Task {
+		return task.New(func(t *task.Internal) {
+			time.Sleep(1 * time.Second)
+			if name == "test" {
+				t.Reject("tests failed")
+				return
+			}
+			fmt.Println(name, "done")
+			t.Resolve(name)
+		})
+	}
+}
+
+func main() {
+	start := time.Now()
+	fmt.Println("START", start)
+
+	g := graph.New()
+	g.Add("fetch", nil, step("fetch"))
+	g.Add("lint", []string{"fetch"}, step("lint"))
+	g.Add("compile", []string{"fetch"}, step("compile"))
+	g.Add("test", []string{"compile"}, step("test"))
+	g.Add("package", []string{"compile", "lint"}, step("package"))
+	g.Add("publish", []string{"package", "test"}, step("publish"))
+
+	results, err := g.Run(graph.Options{MaxParallel: 2})
+	for _, name := range []string{"fetch", "lint", "compile", "test", "package", "publish"} {
+		r := results[name]
+		switch {
+		case r.Skipped:
+			fmt.Println(name, "skipped")
+		case r.Err != nil:
+			fmt.Println(name, "failed")
+		default:
+			fmt.Println(name, "resolved")
+		}
+	}
+	fmt.Println("failed:", err != nil)
+
+	fmt.Println("END", time.Since(start))
+}
@@ -0,0 +1,51 @@
+package main_test
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesovilabs/koazee"
+	"github.com/wesovilabs/koazee/stream"
+)
+
+func TestGraph(t *testing.T) {
+	out, err := exec.Command("go", "run", ".").CombinedOutput()
+	if assert.NoError(t, err) {
+		actual := normaliseCmdOutput(out)
+
+		done := actual.Filter(func(v string) bool { return strings.HasSuffix(v, " done") })
+		c, err := done.Count()
+		assert.Nil(t, err)
+		assert.Equal(t, 4, c)
+		assert.Equal(t, "fetch done", done.At(0).String())
+		assert.Equal(t, "package done", done.At(3).String())
+
+		c, err = actual.Count()
+		assert.Nil(t, err)
+		assert.Equal(t, "test failed", actual.At(c-6).String())
+		assert.Equal(t, "package resolved", actual.At(c-5).String())
+		assert.Equal(t, "publish skipped", actual.At(c-4).String())
+		assert.Equal(t, "failed: true", actual.At(c-3).String())
+		assert.Contains(t, actual.At(c-2).String(), "END 3.0")
+	}
+}
+
+func normaliseCmdOutput(in []byte) stream.Stream {
+	root := strings.ReplaceAll(runtime.GOROOT(), "\\", "/")
+	cwd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+	cwd = strings.ReplaceAll(cwd, "\\", "/")
+
+	out := string(in)
+	out = strings.ReplaceAll(out, "\r\n", "\n")
+	out = strings.ReplaceAll(out, root, "")
+	out = strings.ReplaceAll(out, cwd, "")
+
+	return koazee.StreamOf(strings.Split(out, "\n"))
+}
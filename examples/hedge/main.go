@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/hedge"
+	"github.com/brad-jones/goasync/v2/task"
+)
+
+var calls = 0
+
+func backendAsync() *task.Task {
+	calls++
+	call := calls
+	return task.New(func(t *task.Internal) {
+		fmt.Println("backendAsync: call", call, "started")
+
+		// The first call hits a slow replica
+		latency := 50 * time.Millisecond
+		if call == 1 {
+			latency = 5 * time.Second
+		}
+
+		select {
+		case <-time.After(latency):
+			t.Resolve(fmt.Sprint("response from call ", call))
+		case <-*t.Stopper:
+			fmt.Println("backendAsync: call", call, "stopped")
+		}
+	})
+}
+
+func main() {
+	start := time.Now()
+	fmt.Println("START", start)
+
+	h := hedge.New(hedge.Options{Delay: 100 * time.Millisecond})
+	v, err := h.Do(backendAsync)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("result:", v)
+
+	fmt.Println("END", time.Since(start))
+}
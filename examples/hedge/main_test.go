@@ -0,0 +1,47 @@
+package main_test
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesovilabs/koazee"
+	"github.com/wesovilabs/koazee/stream"
+)
+
+func TestHedge(t *testing.T) {
+	out, err := exec.Command("go", "run", ".").CombinedOutput()
+	if assert.NoError(t, err) {
+		actual := normaliseCmdOutput(out)
+		assert.Equal(t, "backendAsync: call 1 started", actual.At(1).String())
+		assert.Equal(t, "backendAsync: call 2 started", actual.At(2).String())
+		assert.Equal(t, "backendAsync: call 1 stopped", actual.At(3).String())
+		assert.Equal(t, "result: response from call 2", actual.At(4).String())
+
+		elapsed, err := time.ParseDuration(strings.TrimPrefix(actual.At(5).String(), "END "))
+		if assert.NoError(t, err) {
+			assert.GreaterOrEqual(t, int64(elapsed), int64(150*time.Millisecond))
+			assert.Less(t, int64(elapsed), int64(time.Second))
+		}
+	}
+}
+
+func normaliseCmdOutput(in []byte) stream.Stream {
+	root := strings.ReplaceAll(runtime.GOROOT(), "\\", "/")
+	cwd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+	cwd = strings.ReplaceAll(cwd, "\\", "/")
+
+	out := string(in)
+	out = strings.ReplaceAll(out, "\r\n", "\n")
+	out = strings.ReplaceAll(out, root, "")
+	out = strings.ReplaceAll(out, cwd, "")
+
+	return koazee.StreamOf(strings.Split(out, "\n"))
+}
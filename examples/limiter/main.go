@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/await"
+	"github.com/brad-jones/goasync/v2/limiter"
+	"github.com/brad-jones/goasync/v2/task"
+)
+
+func main() {
+	start := time.Now()
+	fmt.Println("START", start)
+
+	// The api allows 2 requests per second
+	l := limiter.NewTokenBucket(limiter.PerSecond(2), 1)
+
+	pages := []int{1, 2, 3, 4, 5}
+	results := await.MustMap(pages, 0, func(page int) *task.Task {
+		return l.Go(func(t *task.Internal) {
+			t.Resolve(fmt.Sprint("page ", page, " fetched"))
+		})
+	})
+
+	for _, v := range results {
+		fmt.Println(v)
+	}
+
+	fmt.Println("END", time.Since(start))
+}
@@ -0,0 +1,41 @@
+package main_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesovilabs/koazee"
+	"github.com/wesovilabs/koazee/stream"
+)
+
+func TestLimiter(t *testing.T) {
+	out, err := exec.Command("go", "run", ".").CombinedOutput()
+	if assert.NoError(t, err) {
+		actual := normaliseCmdOutput(out)
+		for i := 1; i <= 5; i++ {
+			assert.Equal(t, fmt.Sprint("page ", i, " fetched"), actual.At(i).String())
+		}
+		assert.Contains(t, actual.At(6).String(), "END 2.0")
+	}
+}
+
+func normaliseCmdOutput(in []byte) stream.Stream {
+	root := strings.ReplaceAll(runtime.GOROOT(), "\\", "/")
+	cwd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+	cwd = strings.ReplaceAll(cwd, "\\", "/")
+
+	out := string(in)
+	out = strings.ReplaceAll(out, "\r\n", "\n")
+	out = strings.ReplaceAll(out, root, "")
+	out = strings.ReplaceAll(out, cwd, "")
+
+	return koazee.StreamOf(strings.Split(out, "\n"))
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/await"
+	"github.com/brad-jones/goasync/v2/pool"
+	"github.com/brad-jones/goasync/v2/task"
+)
+
+func main() {
+	start := time.Now()
+	fmt.Println("START", start)
+
+	p := pool.New(2, pool.Options{QueueSize: 10})
+
+	tasks := []*task.Task{}
+	for i := 1; i <= 6; i++ {
+		job := i
+		tasks = append(tasks, p.Submit(func(t *task.Internal) {
+			time.Sleep(1 * time.Second)
+			t.Resolve(fmt.Sprint("job ", job, " done"))
+		}))
+	}
+
+	for _, v := range await.MustAll(tasks...) {
+		fmt.Println(v)
+	}
+
+	p.Stop()
+	fmt.Println("completed:", p.Stats().Completed)
+
+	fmt.Println("END", time.Since(start))
+}
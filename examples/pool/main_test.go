@@ -0,0 +1,47 @@
+package main_test
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesovilabs/koazee"
+	"github.com/wesovilabs/koazee/stream"
+)
+
+func TestPool(t *testing.T) {
+	out, err := exec.Command("go", "run", ".").CombinedOutput()
+	if assert.NoError(t, err) {
+		actual := normaliseCmdOutput(out)
+
+		jobs := actual.Filter(func(v string) bool { return strings.HasPrefix(v, "job ") })
+		c, err := jobs.Count()
+		assert.Nil(t, err)
+		assert.Equal(t, 6, c)
+		assert.Equal(t, "job 6 done", jobs.Last().String())
+
+		c, err = actual.Count()
+		assert.Nil(t, err)
+		assert.Equal(t, "completed: 6", actual.At(c-3).String())
+		assert.Contains(t, actual.At(c-2).String(), "END 3.0")
+	}
+}
+
+func normaliseCmdOutput(in []byte) stream.Stream {
+	root := strings.ReplaceAll(runtime.GOROOT(), "\\", "/")
+	cwd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+	cwd = strings.ReplaceAll(cwd, "\\", "/")
+
+	out := string(in)
+	out = strings.ReplaceAll(out, "\r\n", "\n")
+	out = strings.ReplaceAll(out, root, "")
+	out = strings.ReplaceAll(out, cwd, "")
+
+	return koazee.StreamOf(strings.Split(out, "\n"))
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/retry"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/brad-jones/goerr/v2"
+)
+
+var attempts = 0
+
+func flakyAsync() *task.Task {
+	return task.New(func(t *task.Internal) {
+		attempts++
+		if attempts < 3 {
+			fmt.Println("flakyAsync: attempt", attempts, "failed")
+			t.Reject(goerr.New("service unavailable"))
+			return
+		}
+		fmt.Println("flakyAsync: attempt", attempts, "succeeded")
+		t.Resolve("some data")
+	})
+}
+
+func main() {
+	start := time.Now()
+	fmt.Println("START", start)
+
+	v, err := retry.Do(retry.Options{
+		Backoff:     retry.Exponential(250*time.Millisecond, 1*time.Second, 2),
+		MaxAttempts: 5,
+	}, flakyAsync)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("result:", v)
+
+	fmt.Println("END", time.Since(start))
+}
@@ -0,0 +1,53 @@
+package main_test
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesovilabs/koazee"
+	"github.com/wesovilabs/koazee/stream"
+)
+
+func TestRetry(t *testing.T) {
+	out, err := exec.Command("go", "run", ".").CombinedOutput()
+	if assert.NoError(t, err) {
+		actual := normaliseCmdOutput(out)
+
+		flaky := actual.Filter(func(v string) bool { return strings.HasPrefix(v, "flakyAsync:") })
+		c, err := flaky.Count()
+		assert.Nil(t, err)
+		assert.Equal(t, 3, c)
+		assert.Equal(t, "flakyAsync: attempt 3 succeeded", flaky.Last().String())
+
+		c, err = actual.Count()
+		assert.Nil(t, err)
+		assert.Equal(t, "result: some data", actual.At(c-3).String())
+
+		// The attempts are 250ms & then 500ms apart
+		if elapsed, err := time.ParseDuration(strings.TrimPrefix(actual.At(c-2).String(), "END ")); assert.NoError(t, err) {
+			assert.GreaterOrEqual(t, int64(elapsed), int64(750*time.Millisecond))
+			assert.Less(t, int64(elapsed), int64(time.Second))
+		}
+	}
+}
+
+func normaliseCmdOutput(in []byte) stream.Stream {
+	root := strings.ReplaceAll(runtime.GOROOT(), "\\", "/")
+	cwd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+	cwd = strings.ReplaceAll(cwd, "\\", "/")
+
+	out := string(in)
+	out = strings.ReplaceAll(out, "\r\n", "\n")
+	out = strings.ReplaceAll(out, root, "")
+	out = strings.ReplaceAll(out, cwd, "")
+
+	return koazee.StreamOf(strings.Split(out, "\n"))
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/task"
+)
+
+func main() {
+	start := time.Now()
+	fmt.Println("START", start)
+
+	run := 0
+	s := task.Every(1*time.Second, func(t *task.Internal) {
+		run++
+		if run == 2 {
+			t.Reject("health check failed")
+			return
+		}
+		t.Resolve(fmt.Sprint("health check ", run, " ok"))
+	}, task.ScheduleOptions{MaxRuns: 3})
+
+	for v, err := range s.All() {
+		if err != nil {
+			fmt.Println("health check", run, "failed")
+			continue
+		}
+		fmt.Println(v)
+	}
+
+	fmt.Println("END", time.Since(start))
+}
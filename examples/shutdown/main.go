@@ -0,0 +1,42 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/shutdown"
+	"github.com/brad-jones/goasync/v2/task"
+)
+
+func serviceAsync(name string, stopDelay time.Duration) *task.Task {
+	return task.New(func(t *task.Internal) {
+		fmt.Println(name, "started")
+		<-*t.Stopper
+		time.Sleep(stopDelay)
+		fmt.Println(name, "stopped")
+	})
+}
+
+func main() {
+	start := time.Now()
+	fmt.Println("START", start)
+
+	m := shutdown.New(shutdown.Options{Timeout: 1 * time.Second})
+	m.Register("db", "postgres", serviceAsync("postgres", 0))
+	time.Sleep(100 * time.Millisecond)
+	m.Register("workers", "mailer", serviceAsync("mailer", 2*time.Second))
+	time.Sleep(100 * time.Millisecond)
+	m.Register("http", "api", serviceAsync("api", 0))
+
+	// A real daemon would call m.Wait() & block until SIGINT or SIGTERM
+	time.Sleep(1 * time.Second)
+	var failed *shutdown.ErrShutdownFailed
+	if err := m.Shutdown(); errors.As(err, &failed) {
+		for _, f := range failed.Failures {
+			fmt.Println("failed to stop:", f.Phase, f.Name)
+		}
+	}
+
+	fmt.Println("END", time.Since(start))
+}
@@ -0,0 +1,43 @@
+package main_test
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesovilabs/koazee"
+	"github.com/wesovilabs/koazee/stream"
+)
+
+func TestShutdown(t *testing.T) {
+	out, err := exec.Command("go", "run", ".").CombinedOutput()
+	if assert.NoError(t, err) {
+		actual := normaliseCmdOutput(out)
+		assert.Equal(t, "postgres started", actual.At(1).String())
+		assert.Equal(t, "mailer started", actual.At(2).String())
+		assert.Equal(t, "api started", actual.At(3).String())
+		assert.Equal(t, "api stopped", actual.At(4).String())
+		assert.Equal(t, "postgres stopped", actual.At(5).String())
+		assert.Equal(t, "failed to stop: workers mailer", actual.At(6).String())
+		assert.Contains(t, actual.At(7).String(), "END 2.2")
+	}
+}
+
+func normaliseCmdOutput(in []byte) stream.Stream {
+	root := strings.ReplaceAll(runtime.GOROOT(), "\\", "/")
+	cwd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+	cwd = strings.ReplaceAll(cwd, "\\", "/")
+
+	out := string(in)
+	out = strings.ReplaceAll(out, "\r\n", "\n")
+	out = strings.ReplaceAll(out, root, "")
+	out = strings.ReplaceAll(out, cwd, "")
+
+	return koazee.StreamOf(strings.Split(out, "\n"))
+}
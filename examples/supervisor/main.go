@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/supervisor"
+	"github.com/brad-jones/goasync/v2/task"
+)
+
+func main() {
+	start := time.Now()
+	fmt.Println("START", start)
+
+	attempt := 0
+	flaky := &supervisor.Child{
+		Name: "flaky",
+		Start: func() *task.Task {
+			attempt++
+			n := attempt
+			return task.New(func(t *task.Internal) {
+				fmt.Println("flaky started", n)
+				time.Sleep(1 * time.Second)
+				if n < 3 {
+					panic("flaky crashed")
+				}
+				<-*t.Stopper
+				fmt.Println("flaky stopped")
+			})
+		},
+	}
+
+	s := supervisor.New(supervisor.Options{
+		Strategy:    supervisor.OneForOne,
+		MaxRestarts: 5,
+		Period:      10 * time.Second,
+		OnRestart: func(name string, err error) {
+			fmt.Println("restarting", name)
+		},
+	}, flaky)
+
+	time.Sleep(3500 * time.Millisecond)
+	s.Stop()
+
+	fmt.Println("END", time.Since(start))
+}
@@ -0,0 +1,43 @@
+package main_test
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesovilabs/koazee"
+	"github.com/wesovilabs/koazee/stream"
+)
+
+func TestSupervisor(t *testing.T) {
+	out, err := exec.Command("go", "run", ".").CombinedOutput()
+	if assert.NoError(t, err) {
+		actual := normaliseCmdOutput(out)
+		assert.Equal(t, "flaky started 1", actual.At(1).String())
+		assert.Equal(t, "restarting flaky", actual.At(2).String())
+		assert.Equal(t, "flaky started 2", actual.At(3).String())
+		assert.Equal(t, "restarting flaky", actual.At(4).String())
+		assert.Equal(t, "flaky started 3", actual.At(5).String())
+		assert.Equal(t, "flaky stopped", actual.At(6).String())
+		assert.Contains(t, actual.At(7).String(), "END 3.5")
+	}
+}
+
+func normaliseCmdOutput(in []byte) stream.Stream {
+	root := strings.ReplaceAll(runtime.GOROOT(), "\\", "/")
+	cwd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+	cwd = strings.ReplaceAll(cwd, "\\", "/")
+
+	out := string(in)
+	out = strings.ReplaceAll(out, "\r\n", "\n")
+	out = strings.ReplaceAll(out, root, "")
+	out = strings.ReplaceAll(out, cwd, "")
+
+	return koazee.StreamOf(strings.Split(out, "\n"))
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/typed"
+	uuid "github.com/satori/go.uuid"
+)
+
+func randomNameAsync() *typed.Task[string] {
+	return typed.New(func(t *typed.Internal[string]) {
+		time.Sleep(1 * time.Second)
+		t.Resolve(uuid.NewV4().String())
+	})
+}
+
+func nameLengthAsync(name *typed.Task[string]) *typed.Task[int] {
+	return typed.Then(name, func(v string, t *typed.Internal[int]) {
+		time.Sleep(1 * time.Second)
+		t.Resolve(len(v))
+	})
+}
+
+func main() {
+	start := time.Now()
+	fmt.Println("START", start)
+
+	name1 := randomNameAsync()
+	name2 := randomNameAsync()
+
+	for _, length := range typed.MustAll(nameLengthAsync(name1), nameLengthAsync(name2)) {
+		fmt.Println("length:", length)
+	}
+
+	fmt.Println("END", time.Since(start))
+}
@@ -1,6 +1,6 @@
 module github.com/brad-jones/goasync/v2
 
-go 1.15
+go 1.23
 
 require (
 	github.com/brad-jones/goerr/v2 v2.1.3
@@ -8,3 +8,9 @@ require (
 	github.com/stretchr/testify v1.7.0
 	github.com/wesovilabs/koazee v0.0.5
 )
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
+)
@@ -0,0 +1,329 @@
+// Package graph executes tasks that depend on the results of other tasks.
+package graph
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/clock"
+	"github.com/brad-jones/goasync/v2/stop"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/brad-jones/goerr/v2"
+)
+
+// Graph is a set of named nodes that declare their dependencies on
+// each other, much like the targets of a build system. Create new
+// instances with New & add nodes with Add.
+//
+// Executing the graph starts each node as soon as all of it's dependencies
+// have resolved. When a node fails every node that depends on it, directly
+// or indirectly, is skipped but unrelated nodes continue to run.
+type Graph struct {
+	nodes map[string]*node
+	order []string
+	errs  []error
+}
+
+type node struct {
+	name string
+	deps []string
+	fn   func(inputs map[string]interface{}) *task.Task
+}
+
+// Options configures the execution of a Graph.
+type Options struct {
+	// The maximum number of nodes that may run at once,
+	// zero or less means there is no limit.
+	MaxParallel int
+}
+
+// Result is the outcome of a single node.
+type Result struct {
+	// The value the node resolved
+	Value interface{}
+
+	// The error the node rejected, a node that was skipped will
+	// have an ErrDependencyFailed or an ErrTaskStopped.
+	Err error
+
+	// True if the node never ran
+	Skipped bool
+
+	// How long the node took to run
+	Duration time.Duration
+}
+
+// New creates an empty graph.
+func New() *Graph {
+	return &Graph{nodes: map[string]*node{}}
+}
+
+// Add a node to the graph. Once every node named in deps has resolved fn is
+// called with their values, keyed by name, & the returned task is awaited.
+//
+// Mistakes such as duplicate names or unknown dependencies are reported by
+// Validate, this way many nodes can be added without checking each call.
+func (g *Graph) Add(name string, deps []string, fn func(inputs map[string]interface{}) *task.Task) {
+	if _, ok := g.nodes[name]; ok {
+		g.errs = append(g.errs, goerr.Wrap(&ErrDuplicateNode{Name: name}))
+		return
+	}
+	g.nodes[name] = &node{name: name, deps: deps, fn: fn}
+	g.order = append(g.order, name)
+}
+
+// Validate checks the graph can be executed, an ErrCycle is returned if the
+// nodes depend on each other in a loop. Run calls this for you.
+func (g *Graph) Validate() error {
+	if len(g.errs) > 0 {
+		return g.errs[0]
+	}
+
+	for _, name := range g.order {
+		for _, dep := range g.nodes[name].deps {
+			if _, ok := g.nodes[dep]; !ok {
+				return goerr.Wrap(&ErrUnknownDependency{Node: name, Dependency: dep})
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	marks := map[string]int{}
+	path := []string{}
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch marks[name] {
+		case visited:
+			return nil
+		case visiting:
+			for i, v := range path {
+				if v == name {
+					cycle := append(append([]string{}, path[i:]...), name)
+					return goerr.Wrap(&ErrCycle{Path: cycle})
+				}
+			}
+		}
+		marks[name] = visiting
+		path = append(path, name)
+		for _, dep := range g.nodes[name].deps {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		marks[name] = visited
+		return nil
+	}
+
+	for _, name := range g.order {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Run executes the graph & waits for every node to finish, the result of
+// every node is returned keyed by name. If any node failed an ErrNodesFailed
+// is returned along with the results.
+func (g *Graph) Run(options Options) (map[string]*Result, error) {
+	return g.run(options, nil)
+}
+
+// MustRun does the same thing as Run but panics if an error is encountered
+func (g *Graph) MustRun(options Options) map[string]*Result {
+	v, e := g.Run(options)
+	goerr.Check(e)
+	return v
+}
+
+// RunAsync does the same thing as Run but does so asynchronously. The task
+// resolves the results, stopping it will stop all running nodes & skip the
+// nodes that have not yet started.
+func (g *Graph) RunAsync(options Options) *task.Task {
+	return task.New(func(t *task.Internal) {
+		t.Settle(g.run(options, *t.Stopper))
+	})
+}
+
+// run does the actual work for Run & it's variants
+func (g *Graph) run(options Options, stopper <-chan struct{}) (map[string]*Result, error) {
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+
+	maxParallel := options.MaxParallel
+	if maxParallel <= 0 {
+		maxParallel = len(g.order)
+	}
+
+	// Dependents are worked out on every run so that nodes can be added between runs
+	waitingOn := map[string]int{}
+	dependents := map[string][]string{}
+	ready := []string{}
+	for _, name := range g.order {
+		n := g.nodes[name]
+		waitingOn[name] = len(n.deps)
+		for _, dep := range n.deps {
+			dependents[dep] = append(dependents[dep], name)
+		}
+		if len(n.deps) == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	type finished struct {
+		name      string
+		awaitable *task.Task
+		started   time.Time
+	}
+
+	results := map[string]*Result{}
+	running := map[string]*task.Task{}
+	finishedCh := make(chan finished)
+	stopped := false
+
+	var skip func(name string, failed string)
+	skip = func(name string, failed string) {
+		for _, dependent := range dependents[name] {
+			if _, ok := results[dependent]; ok {
+				continue
+			}
+			results[dependent] = &Result{
+				Err:     goerr.Wrap(&ErrDependencyFailed{Node: dependent, Dependency: failed}),
+				Skipped: true,
+			}
+			skip(dependent, failed)
+		}
+	}
+
+	for len(running) > 0 || (len(ready) > 0 && !stopped) {
+		for !stopped && len(ready) > 0 && len(running) < maxParallel {
+			name := ready[0]
+			ready = ready[1:]
+			n := g.nodes[name]
+
+			inputs := map[string]interface{}{}
+			for _, dep := range n.deps {
+				inputs[dep] = results[dep].Value
+			}
+
+			started := clock.Default().Now()
+			awaitable := n.fn(inputs)
+			running[name] = awaitable
+			go func(name string, awaitable *task.Task, started time.Time) {
+				<-*awaitable.Done
+				finishedCh <- finished{name, awaitable, started}
+			}(name, awaitable, started)
+		}
+		if len(running) == 0 {
+			break
+		}
+
+		select {
+		case f := <-finishedCh:
+			delete(running, f.name)
+			v, err := f.awaitable.Result()
+			results[f.name] = &Result{Value: v, Duration: clock.Default().Since(f.started)}
+			if err != nil {
+				results[f.name].Err = goerr.Wrap(err)
+				skip(f.name, f.name)
+				continue
+			}
+			for _, dependent := range dependents[f.name] {
+				waitingOn[dependent]--
+				if _, skipped := results[dependent]; !skipped && waitingOn[dependent] == 0 {
+					ready = append(ready, dependent)
+				}
+			}
+		case <-stopper:
+			stopper = nil
+			stopped = true
+			awaitables := []*task.Task{}
+			for _, awaitable := range running {
+				awaitables = append(awaitables, awaitable)
+			}
+			stop.All(awaitables...)
+		}
+	}
+
+	failedNodes := []string{}
+	failedErrors := []error{}
+	for _, name := range g.order {
+		r, ok := results[name]
+		if !ok {
+			results[name] = &Result{Err: goerr.Wrap(&task.ErrTaskStopped{}), Skipped: true}
+			continue
+		}
+		if r.Err != nil && !r.Skipped {
+			failedNodes = append(failedNodes, name)
+			failedErrors = append(failedErrors, r.Err)
+		}
+	}
+
+	if stopped {
+		return results, goerr.Wrap(&task.ErrTaskStopped{})
+	}
+	if len(failedNodes) > 0 {
+		return results, goerr.Wrap(&ErrNodesFailed{Nodes: failedNodes, Errors: failedErrors})
+	}
+	return results, nil
+}
+
+// ErrCycle is returned when the nodes of a graph depend on each other in a loop.
+type ErrCycle struct {
+	// The names of the nodes in the cycle, the first node is repeated at the end
+	Path []string
+}
+
+func (e *ErrCycle) Error() string {
+	return fmt.Sprintf("graph: dependency cycle detected: %s", strings.Join(e.Path, " -> "))
+}
+
+// ErrDuplicateNode is returned when more than one node has the same name.
+type ErrDuplicateNode struct {
+	Name string
+}
+
+func (e *ErrDuplicateNode) Error() string {
+	return fmt.Sprintf("graph: node %q has already been added", e.Name)
+}
+
+// ErrUnknownDependency is returned when a node depends on a node that does not exist.
+type ErrUnknownDependency struct {
+	Node       string
+	Dependency string
+}
+
+func (e *ErrUnknownDependency) Error() string {
+	return fmt.Sprintf("graph: node %q depends on %q which does not exist", e.Node, e.Dependency)
+}
+
+// ErrDependencyFailed is the error of a node that was skipped because one of
+// it's dependencies, directly or indirectly, failed.
+type ErrDependencyFailed struct {
+	Node       string
+	Dependency string
+}
+
+func (e *ErrDependencyFailed) Error() string {
+	return fmt.Sprintf("graph: node %q was skipped because %q failed", e.Node, e.Dependency)
+}
+
+// ErrNodesFailed is returned by Run when at least one node fails,
+// skipped nodes are not included.
+type ErrNodesFailed struct {
+	Nodes  []string
+	Errors []error
+}
+
+func (e *ErrNodesFailed) Error() string {
+	return fmt.Sprintf("graph: nodes failed: %s", strings.Join(e.Nodes, ", "))
+}
@@ -0,0 +1,202 @@
+package graph_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/graph"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/stretchr/testify/assert"
+)
+
+var errBoom = errors.New("boom")
+
+// value returns a node function that resolves v
+func value(v interface{}) func(inputs map[string]interface{}) *task.Task {
+	return func(inputs map[string]interface{}) *task.Task {
+		return task.Resolved(v)
+	}
+}
+
+func TestRun(t *testing.T) {
+	g := graph.New()
+	g.Add("a", nil, value(1))
+	g.Add("b", []string{"a"}, func(inputs map[string]interface{}) *task.Task {
+		return task.Resolved(inputs["a"].(int) + 1)
+	})
+	g.Add("c", []string{"a"}, func(inputs map[string]interface{}) *task.Task {
+		return task.Resolved(inputs["a"].(int) * 10)
+	})
+	g.Add("d", []string{"b", "c"}, func(inputs map[string]interface{}) *task.Task {
+		return task.Resolved(inputs["b"].(int) + inputs["c"].(int))
+	})
+
+	results, err := g.Run(graph.Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, results["a"].Value)
+	assert.Equal(t, 2, results["b"].Value)
+	assert.Equal(t, 10, results["c"].Value)
+	assert.Equal(t, 12, results["d"].Value)
+	assert.False(t, results["d"].Skipped)
+}
+
+func TestCycle(t *testing.T) {
+	g := graph.New()
+	g.Add("a", []string{"c"}, value(nil))
+	g.Add("b", []string{"a"}, value(nil))
+	g.Add("c", []string{"b"}, value(nil))
+	g.Add("d", nil, value(nil))
+
+	_, err := g.Run(graph.Options{})
+	var cycle *graph.ErrCycle
+	if assert.ErrorAs(t, err, &cycle) {
+		assert.Equal(t, []string{"a", "c", "b", "a"}, cycle.Path)
+	}
+	assert.EqualError(t, err, "graph: dependency cycle detected: a -> c -> b -> a")
+}
+
+func TestSelfCycle(t *testing.T) {
+	g := graph.New()
+	g.Add("a", []string{"a"}, value(nil))
+
+	var cycle *graph.ErrCycle
+	if assert.ErrorAs(t, g.Validate(), &cycle) {
+		assert.Equal(t, []string{"a", "a"}, cycle.Path)
+	}
+}
+
+func TestUnknownDependency(t *testing.T) {
+	g := graph.New()
+	g.Add("a", []string{"missing"}, value(nil))
+
+	var unknown *graph.ErrUnknownDependency
+	if assert.ErrorAs(t, g.Validate(), &unknown) {
+		assert.Equal(t, "a", unknown.Node)
+		assert.Equal(t, "missing", unknown.Dependency)
+	}
+}
+
+func TestDuplicateNode(t *testing.T) {
+	g := graph.New()
+	g.Add("a", nil, value(1))
+	g.Add("a", nil, value(2))
+
+	_, err := g.Run(graph.Options{})
+	var duplicate *graph.ErrDuplicateNode
+	if assert.ErrorAs(t, err, &duplicate) {
+		assert.Equal(t, "a", duplicate.Name)
+	}
+}
+
+func TestTransitiveSkipping(t *testing.T) {
+	called := false
+	g := graph.New()
+	g.Add("a", nil, func(inputs map[string]interface{}) *task.Task {
+		return task.Rejected(errBoom)
+	})
+	g.Add("b", []string{"a"}, value(nil))
+	g.Add("c", []string{"b"}, func(inputs map[string]interface{}) *task.Task {
+		called = true
+		return task.Resolved(nil)
+	})
+	g.Add("d", nil, value("unrelated"))
+
+	results, err := g.Run(graph.Options{})
+
+	var failed *graph.ErrNodesFailed
+	if assert.ErrorAs(t, err, &failed) {
+		assert.Equal(t, []string{"a"}, failed.Nodes)
+	}
+	assert.ErrorIs(t, results["a"].Err, errBoom)
+	assert.False(t, results["a"].Skipped)
+
+	for _, name := range []string{"b", "c"} {
+		var skipped *graph.ErrDependencyFailed
+		assert.True(t, results[name].Skipped)
+		if assert.ErrorAs(t, results[name].Err, &skipped) {
+			assert.Equal(t, name, skipped.Node)
+			assert.Equal(t, "a", skipped.Dependency)
+		}
+	}
+	assert.False(t, called)
+
+	assert.NoError(t, results["d"].Err)
+	assert.Equal(t, "unrelated", results["d"].Value)
+}
+
+func TestMaxParallel(t *testing.T) {
+	started := make(chan *task.Source, 10)
+	g := graph.New()
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		g.Add(name, nil, func(inputs map[string]interface{}) *task.Task {
+			s := task.NewSource()
+			started <- s
+			return s.Task()
+		})
+	}
+	r := g.RunAsync(graph.Options{MaxParallel: 2})
+
+	next := func() *task.Source {
+		select {
+		case s := <-started:
+			return s
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for a node to start")
+			return nil
+		}
+	}
+	idle := func() {
+		select {
+		case <-started:
+			t.Fatal("more than 2 nodes running at once")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	running := []*task.Source{next(), next()}
+	for i := 0; i < 3; i++ {
+		idle()
+		running[0].Resolve(i)
+		running = append(running[1:], next())
+	}
+	idle()
+	running[0].Resolve(nil)
+	running[1].Resolve(nil)
+
+	v, err := r.Result()
+	assert.NoError(t, err)
+	assert.Len(t, v, 5)
+}
+
+func TestRunAsyncStop(t *testing.T) {
+	started := make(chan struct{})
+	var a *task.Task
+	called := false
+
+	g := graph.New()
+	g.Add("a", nil, func(inputs map[string]interface{}) *task.Task {
+		a = task.New(func(t *task.Internal) {
+			close(started)
+			<-*t.Stopper
+		})
+		return a
+	})
+	g.Add("b", []string{"a"}, value(nil))
+	g.Add("c", nil, func(inputs map[string]interface{}) *task.Task {
+		called = true
+		return task.Resolved(nil)
+	})
+	r := g.RunAsync(graph.Options{MaxParallel: 1})
+
+	<-started
+	r.Stop()
+
+	_, err := r.Result()
+	assert.ErrorAs(t, err, new(*task.ErrTaskStopped))
+	assert.Equal(t, task.StateStopped, r.State())
+	assert.Equal(t, task.StateStopped, a.State())
+
+	// Nodes that had not yet started never do
+	assert.False(t, called)
+}
@@ -0,0 +1,180 @@
+// Package hedge reduces tail latency by racing additional copies of slow tasks.
+package hedge
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/await"
+	"github.com/brad-jones/goasync/v2/clock"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/brad-jones/goerr/v2"
+)
+
+// Options controls when additional copies of a task are started.
+type Options struct {
+	// How long to wait after starting a copy before starting the next copy,
+	// the next copy is started straight away if a copy fails. When Percentile
+	// is set this is only used until enough latencies have been recorded.
+	Delay time.Duration
+
+	// The maximum number of copies to run, including the first.
+	// Defaults to 2.
+	MaxCopies int
+
+	// When set (eg: 0.95) the delay is learned from the latencies of previous
+	// successful tasks, additional copies are started once a task has taken
+	// longer than this percentile of previous tasks.
+	Percentile float64
+
+	// The number of latencies that must be recorded before the Percentile is
+	// used, defaults to 10.
+	MinSamples int
+
+	// The number of latencies to remember, defaults to 100.
+	MaxSamples int
+
+	// Used to measure latencies & wait between copies, defaults to clock.Default()
+	Clock clock.Clock
+}
+
+// Hedger starts tasks & hedges them when they take too long,
+// create new instances with New.
+//
+// A Hedger is safe for concurrent use, share one Hedger between all calls
+// to the same backend so that it can learn the latency of that backend.
+type Hedger struct {
+	options Options
+
+	// Protects samples & next
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+}
+
+// New creates a new Hedger.
+func New(options Options) *Hedger {
+	if options.MaxCopies <= 0 {
+		options.MaxCopies = 2
+	}
+	if options.MinSamples <= 0 {
+		options.MinSamples = 10
+	}
+	if options.MaxSamples <= 0 {
+		options.MaxSamples = 100
+	}
+	options.Clock = clock.Or(options.Clock)
+	return &Hedger{options: options}
+}
+
+// Do will call factory & await the returned task, if the task has not
+// resolved after the hedging delay, or as soon as it rejects, factory will
+// be called again, up to MaxCopies times. The first value to be resolved is
+// returned & all other copies are stopped. An ErrTaskFailed is returned if
+// every copy rejects.
+func (h *Hedger) Do(factory func() *task.Task) (interface{}, error) {
+	return h.DoAsync(factory).Result()
+}
+
+// MustDo does the same thing as Do but panics if an error is encountered
+func (h *Hedger) MustDo(factory func() *task.Task) interface{} {
+	v, e := h.Do(factory)
+	goerr.Check(e)
+	return v
+}
+
+// DoAsync does the same thing as Do but does so asynchronously.
+func (h *Hedger) DoAsync(factory func() *task.Task) *task.Task {
+	return task.New(func(t *task.Internal) {
+		delay := h.Delay()
+
+		copies := []*task.Task{}
+		var previousStarted, previousFailed chan struct{}
+		for i := 0; i < h.options.MaxCopies; i++ {
+			first, prevStarted, prevFailed := i == 0, previousStarted, previousFailed
+			started, failed := make(chan struct{}), make(chan struct{})
+			previousStarted, previousFailed = started, failed
+
+			copies = append(copies, t.Spawn(func(t *task.Internal) {
+				// Each copy starts once the previous copy has been running
+				// for the delay, or as soon as the previous copy fails
+				if !first {
+					select {
+					case <-*t.Stopper:
+						return
+					case <-prevStarted:
+					case <-prevFailed:
+					}
+					if delay > 0 {
+						timer := h.options.Clock.NewTimer(delay)
+						select {
+						case <-*t.Stopper:
+							timer.Stop()
+							return
+						case <-prevFailed:
+							timer.Stop()
+						case <-timer.C():
+						}
+					}
+					if t.ShouldStop() {
+						return
+					}
+				}
+
+				start := h.options.Clock.Now()
+				close(started)
+				v, err := t.Adopt(factory()).Result()
+				if err != nil {
+					close(failed)
+					t.Reject(err)
+					return
+				}
+				h.record(h.options.Clock.Since(start))
+				t.Resolve(v)
+			}))
+		}
+
+		t.Settle(await.AnySuccess(copies...))
+	})
+}
+
+// Delay returns how long the Hedger will currently wait before starting an
+// additional copy of a task.
+func (h *Hedger) Delay() time.Duration {
+	if h.options.Percentile <= 0 {
+		return h.options.Delay
+	}
+
+	h.mu.Lock()
+	if len(h.samples) < h.options.MinSamples {
+		h.mu.Unlock()
+		return h.options.Delay
+	}
+	sorted := make([]time.Duration, len(h.samples))
+	copy(sorted, h.samples)
+	h.mu.Unlock()
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	i := int(math.Ceil(h.options.Percentile*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(sorted) {
+		i = len(sorted) - 1
+	}
+	return sorted[i]
+}
+
+// record remembers the latency of a successful task
+func (h *Hedger) record(latency time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.samples) < h.options.MaxSamples {
+		h.samples = append(h.samples, latency)
+		return
+	}
+	h.samples[h.next] = latency
+	h.next = (h.next + 1) % h.options.MaxSamples
+}
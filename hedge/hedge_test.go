@@ -0,0 +1,157 @@
+package hedge_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/await"
+	"github.com/brad-jones/goasync/v2/clock/fake"
+	"github.com/brad-jones/goasync/v2/hedge"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/stretchr/testify/assert"
+)
+
+// backend records the tasks created by a factory
+type backend struct {
+	mu    sync.Mutex
+	tasks []*task.Task
+}
+
+func (b *backend) add(t *task.Task) *task.Task {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tasks = append(b.tasks, t)
+	return t
+}
+
+func (b *backend) calls() []*task.Task {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*task.Task{}, b.tasks...)
+}
+
+// after returns a task that resolves v once the clock has been advanced by d
+func after(c *fake.Clock, d time.Duration, v interface{}) *task.Task {
+	return task.New(func(t *task.Internal) {
+		timer := c.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-timer.C():
+			t.Resolve(v)
+		case <-*t.Stopper:
+		}
+	})
+}
+
+func TestFastFirstCopyIsNotHedged(t *testing.T) {
+	c := fake.New(time.Now())
+	b := &backend{}
+	h := hedge.New(hedge.Options{Delay: time.Second, Clock: c})
+
+	v, err := h.Do(func() *task.Task { return b.add(task.Resolved("first")) })
+	assert.NoError(t, err)
+	assert.Equal(t, "first", v)
+	assert.Len(t, b.calls(), 1)
+}
+
+func TestSlowFirstCopyIsHedged(t *testing.T) {
+	c := fake.New(time.Now())
+	b := &backend{}
+	h := hedge.New(hedge.Options{Delay: time.Second, MaxCopies: 3, Clock: c})
+
+	r := h.DoAsync(func() *task.Task {
+		if len(b.calls()) == 0 {
+			return b.add(after(c, time.Hour, "first"))
+		}
+		return b.add(task.Resolved("second"))
+	})
+
+	// The first copy's timer, plus the timer of the second copy,
+	// the third copy waits for the second copy to start
+	c.BlockUntil(2)
+	c.Advance(time.Second)
+
+	v, err := r.Result()
+	assert.NoError(t, err)
+	assert.Equal(t, "second", v)
+
+	// The third copy is stopped before it is started
+	calls := b.calls()
+	if assert.Len(t, calls, 2) {
+		assert.Equal(t, task.StateStopped, calls[0].State())
+		assert.Equal(t, task.StateResolved, calls[1].State())
+	}
+}
+
+func TestEveryCopyRejects(t *testing.T) {
+	h := hedge.New(hedge.Options{Delay: time.Millisecond})
+	_, err := h.Do(func() *task.Task { return task.Rejected(errors.New("boom")) })
+	var failed *await.ErrTaskFailed
+	assert.ErrorAs(t, err, &failed)
+}
+
+func TestFailedCopyIsHedgedStraightAway(t *testing.T) {
+	c := fake.New(time.Now())
+	b := &backend{}
+	h := hedge.New(hedge.Options{Delay: time.Hour, MaxCopies: 3, Clock: c})
+
+	r := h.DoAsync(func() *task.Task {
+		switch len(b.calls()) {
+		case 0:
+			return b.add(task.Rejected(errors.New("boom")))
+		case 1:
+			return b.add(after(c, time.Minute, "second"))
+		}
+		return b.add(task.Resolved("third"))
+	})
+
+	// The second copy starts without waiting out the delay, the
+	// third copy still waits for the delay as the second is running
+	c.BlockUntil(2)
+	assert.Len(t, b.calls(), 2)
+	c.Advance(time.Minute)
+
+	v, err := r.Result()
+	assert.NoError(t, err)
+	assert.Equal(t, "second", v)
+	assert.Len(t, b.calls(), 2)
+}
+
+func TestDelayIsLearned(t *testing.T) {
+	c := fake.New(time.Now())
+	h := hedge.New(hedge.Options{
+		Delay:      time.Hour,
+		Percentile: 0.5,
+		MinSamples: 3,
+		MaxSamples: 4,
+		Clock:      c,
+	})
+
+	for _, step := range []struct {
+		latency time.Duration
+		delay   time.Duration
+	}{
+		// The default delay is used until there are enough samples
+		{time.Second, time.Hour},
+		{2 * time.Second, time.Hour},
+		{3 * time.Second, 2 * time.Second},
+		{4 * time.Second, 2 * time.Second},
+
+		// Once full the oldest samples are replaced
+		{10 * time.Second, 3 * time.Second},
+		{10 * time.Second, 4 * time.Second},
+	} {
+		r := h.DoAsync(func() *task.Task { return after(c, step.latency, "ok") })
+
+		// The first copy's timer & the timer of the second copy
+		c.BlockUntil(2)
+		c.Advance(step.latency)
+
+		v, err := r.Result()
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", v)
+		assert.Equal(t, step.delay, h.Delay())
+	}
+}
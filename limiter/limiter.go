@@ -0,0 +1,204 @@
+// Package limiter limits the rate at which tasks are started.
+package limiter
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/clock"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/brad-jones/goerr/v2"
+)
+
+// Rate is a number of events per duration, eg: `limiter.Rate{Limit: 10, Per: time.Second}`
+type Rate struct {
+	Limit int
+	Per   time.Duration
+}
+
+// PerSecond is a shortcut for a Rate of n per second.
+func PerSecond(n int) Rate {
+	return Rate{Limit: n, Per: time.Second}
+}
+
+// interval returns the time between events
+func (r Rate) interval() time.Duration {
+	if r.Limit <= 0 {
+		return 0
+	}
+	return r.Per / time.Duration(r.Limit)
+}
+
+// Limiter decides when tasks may start, create new instances with
+// NewTokenBucket or NewLeakyBucket.
+//
+// A Limiter is safe for concurrent use, share one Limiter between every pool,
+// await.Map call, etc that calls the same API so that they all share the quota.
+type Limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+
+	// Used by the token bucket
+	tokens float64
+	burst  int
+	last   time.Time
+
+	// Used by the leaky bucket
+	next     time.Time
+	capacity int
+	leaky    bool
+}
+
+// NewTokenBucket creates a limiter that allows up to burst tasks to start at
+// once & then refills at the given rate. The bucket starts out full.
+func NewTokenBucket(rate Rate, burst int) *Limiter {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &Limiter{
+		interval: rate.interval(),
+		tokens:   float64(burst),
+		burst:    burst,
+	}
+}
+
+// NewLeakyBucket creates a limiter that starts tasks at a steady rate, with
+// no bursts. At most capacity tasks may be waiting to start, once full new
+// tasks are rejected with an ErrBucketFull. A capacity of zero or less means
+// there is no limit.
+func NewLeakyBucket(rate Rate, capacity int) *Limiter {
+	return &Limiter{
+		interval: rate.interval(),
+		capacity: capacity,
+		leaky:    true,
+	}
+}
+
+// Go creates a task that stays pending until the limiter allows it to start.
+// Stopping the task while it is waiting means fn is never executed, a token
+// bucket gets the token back where as a leaky bucket leaves the place empty.
+//
+// Accepts `func()` or `func(t *task.Internal)`
+//
+// For example, at most 10 requests per second with at most 4 in flight:
+// 	l := limiter.NewTokenBucket(limiter.PerSecond(10), 1)
+// 	results, err := await.Map(urls, 4, func(url string) *task.Task {
+// 		return l.Go(func(t *task.Internal) { t.Settle(download(url)) })
+// 	})
+func (l *Limiter) Go(fn interface{}) *task.Task {
+	wait, err := l.reserve()
+	if err != nil {
+		return task.Rejected(err)
+	}
+
+	t := task.NewPending(fn)
+	if wait <= 0 {
+		t.Start()
+		return t
+	}
+
+	go func() {
+		timer := clock.Default().NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C():
+		case <-*t.Stopper:
+			l.cancel()
+		}
+		// A stopped task finishes without executing fn
+		t.Start()
+	}()
+
+	return t
+}
+
+// Wait blocks until the limiter allows the caller to continue or stopper is
+// closed, in which case an ErrTaskStopped is returned. Use this to limit work
+// that is already running in a task, eg: a task submitted to a pool.
+//
+// 	p.Submit(func(t *task.Internal) {
+// 		if err := l.Wait(*t.Stopper); err != nil {
+// 			t.Reject(err)
+// 			return
+// 		}
+// 		t.Settle(callApi())
+// 	})
+func (l *Limiter) Wait(stopper <-chan struct{}) error {
+	wait, err := l.reserve()
+	if err != nil {
+		return err
+	}
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := clock.Default().NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		return nil
+	case <-stopper:
+		l.cancel()
+		return goerr.Wrap(&task.ErrTaskStopped{})
+	}
+}
+
+// reserve takes a place in the bucket & returns how long to wait for it
+func (l *Limiter) reserve() (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := clock.Default().Now()
+	if l.interval <= 0 {
+		return 0, nil
+	}
+
+	if l.leaky {
+		at := l.next
+		if at.Before(now) {
+			at = now
+		}
+		wait := at.Sub(now)
+		if l.capacity > 0 && int(wait/l.interval) > l.capacity {
+			return 0, goerr.Wrap(&ErrBucketFull{Capacity: l.capacity})
+		}
+		l.next = at.Add(l.interval)
+		return wait, nil
+	}
+
+	if !l.last.IsZero() {
+		l.tokens += float64(now.Sub(l.last)) / float64(l.interval)
+		if l.tokens > float64(l.burst) {
+			l.tokens = float64(l.burst)
+		}
+	}
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0, nil
+	}
+	return time.Duration(-l.tokens * float64(l.interval)), nil
+}
+
+// cancel gives back a place that was reserved but not used. A leaky bucket
+// keeps the place empty, giving it to a later task would start that task at
+// the same time as the task that reserved the last place.
+func (l *Limiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.leaky {
+		return
+	}
+	// The bucket may have been refilled since the place was reserved
+	l.tokens = min(l.tokens+1, float64(l.burst))
+}
+
+// ErrBucketFull is returned by a leaky bucket when too many tasks are waiting to start.
+type ErrBucketFull struct {
+	Capacity int
+}
+
+func (e *ErrBucketFull) Error() string {
+	return fmt.Sprintf("limiter: the bucket is full, %d tasks are already waiting", e.Capacity)
+}
@@ -0,0 +1,35 @@
+package limiter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/clock"
+	"github.com/brad-jones/goasync/v2/clock/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+// A task can be stopped after the bucket was refilled, in which case
+// giving it's token back must not overfill the bucket.
+func TestCancelDoesNotOverfill(t *testing.T) {
+	c := fake.New(time.Now())
+	defer clock.SetDefault(clock.SetDefault(c))
+
+	l := NewTokenBucket(PerSecond(10), 1)
+	l.reserve()
+	l.reserve()
+	l.reserve()
+
+	c.Advance(time.Second)
+	l.reserve()
+	l.cancel()
+	l.cancel()
+
+	wait, err := l.reserve()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), wait)
+
+	wait, err = l.reserve()
+	assert.NoError(t, err)
+	assert.Equal(t, 100*time.Millisecond, wait)
+}
@@ -0,0 +1,138 @@
+package limiter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/clock"
+	"github.com/brad-jones/goasync/v2/clock/fake"
+	"github.com/brad-jones/goasync/v2/limiter"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder returns a function that records how long after start it was called
+func recorder(c *fake.Clock) (chan time.Duration, func()) {
+	start := c.Now()
+	started := make(chan time.Duration, 10)
+	return started, func() { started <- c.Since(start) }
+}
+
+// next waits for the next start, giving up after a second
+func next(started chan time.Duration) time.Duration {
+	select {
+	case d := <-started:
+		return d
+	case <-time.After(time.Second):
+		return -1
+	}
+}
+
+// nothingStarted asserts that nothing is received for a little while
+func nothingStarted(t *testing.T, started chan time.Duration) {
+	select {
+	case d := <-started:
+		assert.Fail(t, "unexpected start", "started after %s", d)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	c := fake.New(time.Now())
+	defer clock.SetDefault(clock.SetDefault(c))
+	started, fn := recorder(c)
+
+	l := limiter.NewTokenBucket(limiter.PerSecond(10), 2)
+	l.Go(fn)
+	l.Go(fn)
+	third := l.Go(fn)
+	assert.Equal(t, time.Duration(0), next(started))
+	assert.Equal(t, time.Duration(0), next(started))
+	nothingStarted(t, started)
+
+	c.BlockUntil(1)
+	c.Advance(100 * time.Millisecond)
+	assert.Equal(t, 100*time.Millisecond, next(started))
+	assert.NoError(t, third.Wait())
+}
+
+func TestTokenBucketCancel(t *testing.T) {
+	c := fake.New(time.Now())
+	defer clock.SetDefault(clock.SetDefault(c))
+	started, fn := recorder(c)
+
+	l := limiter.NewTokenBucket(limiter.PerSecond(10), 1)
+	l.Go(fn)
+	waiting := l.Go(fn)
+	next(started)
+	c.BlockUntil(1)
+	waiting.Stop()
+	assert.Equal(t, task.StateStopped, waiting.State())
+
+	// The stopped task gave it's token back
+	l.Go(fn)
+	c.BlockUntil(1)
+	c.Advance(100 * time.Millisecond)
+	assert.Equal(t, 100*time.Millisecond, next(started))
+}
+
+func TestLeakyBucket(t *testing.T) {
+	c := fake.New(time.Now())
+	defer clock.SetDefault(clock.SetDefault(c))
+	started, fn := recorder(c)
+
+	l := limiter.NewLeakyBucket(limiter.PerSecond(10), 2)
+	l.Go(fn)
+	l.Go(fn)
+	l.Go(fn)
+	var full *limiter.ErrBucketFull
+	assert.ErrorAs(t, l.Go(fn).Wait(), &full)
+
+	assert.Equal(t, time.Duration(0), next(started))
+	for _, expected := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond} {
+		c.BlockUntil(1)
+		nothingStarted(t, started)
+		c.Advance(100 * time.Millisecond)
+		assert.Equal(t, expected, next(started))
+	}
+}
+
+func TestLeakyBucketCancel(t *testing.T) {
+	c := fake.New(time.Now())
+	defer clock.SetDefault(clock.SetDefault(c))
+	started, fn := recorder(c)
+
+	l := limiter.NewLeakyBucket(limiter.PerSecond(10), 0)
+	l.Go(fn)
+	first := l.Go(fn)
+	l.Go(fn)
+	next(started)
+	c.BlockUntil(2)
+	first.Stop()
+
+	// The stopped task's place is left empty rather than
+	// letting the next task start alongside the last task
+	l.Go(fn)
+	c.BlockUntil(2)
+	c.Advance(200 * time.Millisecond)
+	assert.Equal(t, 200*time.Millisecond, next(started))
+	nothingStarted(t, started)
+	c.Advance(100 * time.Millisecond)
+	assert.Equal(t, 300*time.Millisecond, next(started))
+}
+
+func TestWait(t *testing.T) {
+	c := fake.New(time.Now())
+	defer clock.SetDefault(clock.SetDefault(c))
+
+	l := limiter.NewLeakyBucket(limiter.PerSecond(10), 0)
+	assert.NoError(t, l.Wait(nil))
+
+	stopper := make(chan struct{})
+	errs := make(chan error)
+	go func() { errs <- l.Wait(stopper) }()
+	c.BlockUntil(1)
+	close(stopper)
+	var stopped *task.ErrTaskStopped
+	assert.ErrorAs(t, <-errs, &stopped)
+}
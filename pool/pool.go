@@ -0,0 +1,165 @@
+// Package pool executes tasks on a bounded number of workers.
+package pool
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/brad-jones/goerr/v2"
+)
+
+// Policy decides what happens when a task is submitted to a full queue.
+type Policy int
+
+const (
+	// Block will cause Submit to block until there is room in the queue.
+	Block Policy = iota
+
+	// Reject will cause Submit to return a task that has been
+	// rejected with an ErrQueueFull.
+	Reject
+)
+
+// Options configures a Pool.
+type Options struct {
+	// The number of tasks that may be waiting for a worker,
+	// zero means a task is only accepted once a worker is free.
+	QueueSize int
+
+	// What to do when the queue is full, defaults to Block.
+	Policy Policy
+}
+
+// Pool executes submitted tasks on a fixed number of workers,
+// create new instances with New.
+//
+// The pool itself is a task, stopping it will gracefully drain the pool.
+// No new tasks will be accepted but any queued tasks will still be executed,
+// the pool is done once every queued task is done. So the stop package can
+// be used to drain many pools at once, eg: `stop.All(pool1.Task, pool2.Task)`
+type Pool struct {
+	*task.Task
+
+	options Options
+	queue   chan *task.Task
+
+	// Protects closed & the queue from being written to once closed
+	mu     sync.RWMutex
+	closed bool
+
+	queued    int64
+	running   int64
+	completed int64
+}
+
+// Stats is a point in time snapshot of the work a pool is doing.
+type Stats struct {
+	// The number of tasks waiting for a worker
+	Queued int
+
+	// The number of tasks currently executing
+	Running int
+
+	// The number of tasks that have finished executing
+	Completed int
+}
+
+// New creates a pool with the given number of workers.
+func New(workers int, options Options) *Pool {
+	p := &Pool{
+		options: options,
+		queue:   make(chan *task.Task, options.QueueSize),
+	}
+
+	p.Task = task.New(func(t *task.Internal) {
+		for i := 0; i < workers; i++ {
+			t.Spawn(p.work)
+		}
+	})
+
+	// The workers return once the queue is closed & drained,
+	// the pool is done once all of it's workers are done.
+	go func() {
+		<-*p.Stopper
+		p.mu.Lock()
+		p.closed = true
+		close(p.queue)
+		p.mu.Unlock()
+	}()
+
+	return p
+}
+
+// Submit queues fn to be executed by a worker. The returned task will be
+// pending until a worker picks it up, it can be awaited like any other task.
+// Accepts `func()` or `func(t *task.Internal)`
+//
+// If the pool has been stopped the returned task will be rejected with
+// an ErrPoolStopped.
+func (p *Pool) Submit(fn interface{}) *task.Task {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return task.Rejected(goerr.Wrap(&ErrPoolStopped{}))
+	}
+
+	t := task.NewPending(fn)
+	atomic.AddInt64(&p.queued, 1)
+
+	if p.options.Policy == Reject {
+		select {
+		case p.queue <- t:
+			return t
+		default:
+			atomic.AddInt64(&p.queued, -1)
+			return task.Rejected(goerr.Wrap(&ErrQueueFull{}))
+		}
+	}
+
+	select {
+	case p.queue <- t:
+		return t
+	case <-*p.Stopper:
+		atomic.AddInt64(&p.queued, -1)
+		return task.Rejected(goerr.Wrap(&ErrPoolStopped{}))
+	}
+}
+
+// Stats returns a snapshot of the work the pool is doing.
+func (p *Pool) Stats() Stats {
+	return Stats{
+		Queued:    int(atomic.LoadInt64(&p.queued)),
+		Running:   int(atomic.LoadInt64(&p.running)),
+		Completed: int(atomic.LoadInt64(&p.completed)),
+	}
+}
+
+// work executes queued tasks until the queue is closed
+func (p *Pool) work() {
+	for t := range p.queue {
+		atomic.AddInt64(&p.queued, -1)
+		atomic.AddInt64(&p.running, 1)
+		t.Run()
+		atomic.AddInt64(&p.running, -1)
+		atomic.AddInt64(&p.completed, 1)
+	}
+}
+
+// ErrQueueFull is returned by tasks submitted to a
+// pool using the Reject policy when the queue is full.
+type ErrQueueFull struct {
+}
+
+func (e *ErrQueueFull) Error() string {
+	return "pool: the queue is full"
+}
+
+// ErrPoolStopped is returned by tasks submitted to a pool that has been stopped.
+type ErrPoolStopped struct {
+}
+
+func (e *ErrPoolStopped) Error() string {
+	return "pool: the pool has been stopped"
+}
@@ -0,0 +1,100 @@
+package pool_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/pool"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoolLimitsConcurrency(t *testing.T) {
+	p := pool.New(4, pool.Options{QueueSize: 100})
+	var max, cur int64
+	tasks := []*task.Task{}
+	for i := 0; i < 50; i++ {
+		i := i
+		tasks = append(tasks, p.Submit(func(t *task.Internal) {
+			n := atomic.AddInt64(&cur, 1)
+			for {
+				m := atomic.LoadInt64(&max)
+				if n <= m || atomic.CompareAndSwapInt64(&max, m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt64(&cur, -1)
+			t.Resolve(i)
+		}))
+	}
+
+	for i, tk := range tasks {
+		v, err := tk.Result()
+		assert.NoError(t, err)
+		assert.Equal(t, i, v)
+	}
+	assert.LessOrEqual(t, atomic.LoadInt64(&max), int64(4))
+
+	p.Stop()
+	assert.Equal(t, pool.Stats{Completed: 50}, p.Stats())
+}
+
+func TestPoolDrainsQueueWhenStopped(t *testing.T) {
+	p := pool.New(1, pool.Options{QueueSize: 10})
+	release := make(chan struct{})
+	first := p.Submit(func() { <-release })
+	queued := []*task.Task{}
+	for i := 0; i < 5; i++ {
+		queued = append(queued, p.Submit(func() {}))
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+	p.Stop()
+
+	assert.Equal(t, task.StateCompleted, first.State())
+	for _, tk := range queued {
+		assert.Equal(t, task.StateCompleted, tk.State())
+	}
+
+	var stopped *pool.ErrPoolStopped
+	assert.ErrorAs(t, p.Submit(func() {}).Wait(), &stopped)
+}
+
+func TestPoolRejectPolicy(t *testing.T) {
+	p := pool.New(1, pool.Options{Policy: pool.Reject})
+	release := make(chan struct{})
+
+	// Without a queue a task is only accepted once the worker is waiting for one
+	var first *task.Task
+	assert.Eventually(t, func() bool {
+		first = p.Submit(func() { <-release })
+		return !first.IsCompleted()
+	}, time.Second, time.Millisecond)
+
+	var full *pool.ErrQueueFull
+	assert.ErrorAs(t, p.Submit(func() {}).Wait(), &full)
+
+	close(release)
+	p.Stop()
+	assert.NoError(t, first.Wait())
+	assert.Equal(t, task.StateCompleted, first.State())
+}
+
+func TestPoolStoppedBeforeStarting(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		p := pool.New(2, pool.Options{QueueSize: 1})
+		queued := p.Submit(func() {})
+		p.Stop()
+
+		var stopped *pool.ErrPoolStopped
+		assert.ErrorAs(t, p.Submit(func() {}).Wait(), &stopped)
+
+		assert.NoError(t, queued.Wait())
+		assert.Equal(t, task.StateCompleted, queued.State())
+	}
+}
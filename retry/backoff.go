@@ -0,0 +1,84 @@
+package retry
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff decides how long to wait before the next attempt.
+type Backoff interface {
+	// Delay is given the number of the retry that is about to happen, starting
+	// at 1, along with the previous delay (zero for the first retry).
+	Delay(retry int, previous time.Duration) time.Duration
+}
+
+// BackoffFunc allows an ordinary function to be used as a Backoff.
+type BackoffFunc func(retry int, previous time.Duration) time.Duration
+
+// Delay calls f(retry, previous)
+func (f BackoffFunc) Delay(retry int, previous time.Duration) time.Duration {
+	return f(retry, previous)
+}
+
+// Constant waits the same amount of time before every retry.
+func Constant(d time.Duration) Backoff {
+	return BackoffFunc(func(retry int, previous time.Duration) time.Duration {
+		return d
+	})
+}
+
+// Exponential waits initial * factor^(retry-1) before every retry,
+// capped at max. A max of zero means there is no cap.
+func Exponential(initial, max time.Duration, factor float64) Backoff {
+	return BackoffFunc(func(retry int, previous time.Duration) time.Duration {
+		d := float64(initial) * math.Pow(factor, float64(retry-1))
+		if max > 0 && d > float64(max) {
+			return max
+		}
+		return clamp(d)
+	})
+}
+
+// Jittered randomises the delay of another Backoff by up to +/- fraction,
+// eg: a fraction of 0.5 turns a 1s delay into anything between 0.5s and 1.5s.
+func Jittered(b Backoff, fraction float64) Backoff {
+	return BackoffFunc(func(retry int, previous time.Duration) time.Duration {
+		d := float64(b.Delay(retry, previous))
+		return clamp(d + d*fraction*(rand.Float64()*2-1))
+	})
+}
+
+// Decorrelated implements the "decorrelated jitter" algorithm, each delay is
+// a random value between base and 3 times the previous delay, capped at max.
+//
+// See: https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
+func Decorrelated(base, max time.Duration) Backoff {
+	return BackoffFunc(func(retry int, previous time.Duration) time.Duration {
+		if previous < base {
+			previous = base
+		}
+		upper := time.Duration(math.MaxInt64)
+		if previous < upper/3 {
+			upper = previous * 3
+		}
+		span := int64(upper - base)
+		if span < math.MaxInt64 {
+			span++
+		}
+		d := base + time.Duration(rand.Int63n(span))
+		if max > 0 && d > max {
+			return max
+		}
+		return d
+	})
+}
+
+// clamp converts d to a duration, delays too long to be represented
+// become the longest possible duration rather than overflowing.
+func clamp(d float64) time.Duration {
+	if d >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(d)
+}
@@ -0,0 +1,115 @@
+// Package retry re-runs tasks that reject.
+package retry
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/clock"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/brad-jones/goerr/v2"
+)
+
+// Options controls how & when a task is retried.
+type Options struct {
+	// Decides how long to wait between attempts,
+	// if nil the next attempt is started immediately.
+	Backoff Backoff
+
+	// The maximum number of attempts to make, zero means no limit.
+	MaxAttempts int
+
+	// The maximum amount of time to spend retrying, zero means no limit.
+	// An attempt that is already running will not be interrupted, instead
+	// no further attempts are made once this time has passed.
+	MaxElapsed time.Duration
+
+	// Decides if an error is worth retrying, if nil all errors are retried.
+	Retryable func(err error) bool
+
+	// Used to measure elapsed time & wait between attempts, defaults to clock.Default()
+	Clock clock.Clock
+}
+
+// Do will call factory & await the returned task, if the task rejects then
+// factory will be called again until the task resolves or the options say
+// we should give up, in which case an ErrAttemptsFailed is returned.
+func Do(opts Options, factory func() *task.Task) (interface{}, error) {
+	return DoAsync(opts, factory).Result()
+}
+
+// MustDo does the same thing as Do but panics if an error is encountered
+func MustDo(opts Options, factory func() *task.Task) interface{} {
+	v, e := Do(opts, factory)
+	goerr.Check(e)
+	return v
+}
+
+// DoAsync does the same thing as Do but does so asynchronously.
+//
+// Stopping the returned task will stop the current attempt
+// or interrupt the wait between attempts.
+func DoAsync(opts Options, factory func() *task.Task) *task.Task {
+	return task.New(func(t *task.Internal) {
+		c := clock.Or(opts.Clock)
+		start := c.Now()
+		errs := []error{}
+		delay := time.Duration(0)
+
+		for attempt := 1; ; attempt++ {
+			// Attempts are not adopted, as a child the task would remember
+			// every attempt for as long as it keeps on retrying.
+			current := factory()
+			select {
+			case <-*current.Done:
+			case <-*t.Stopper:
+				current.Stop()
+			}
+			v, err := current.Result()
+			if err == nil {
+				t.Resolve(v)
+				return
+			}
+			errs = append(errs, err)
+
+			if t.ShouldStop() ||
+				(opts.Retryable != nil && !opts.Retryable(err)) ||
+				(opts.MaxAttempts > 0 && attempt >= opts.MaxAttempts) ||
+				(opts.MaxElapsed > 0 && c.Since(start) >= opts.MaxElapsed) {
+				t.Reject(&ErrAttemptsFailed{Errors: errs})
+				return
+			}
+
+			if opts.Backoff != nil {
+				delay = opts.Backoff.Delay(attempt, delay)
+				if opts.MaxElapsed > 0 && c.Since(start)+delay > opts.MaxElapsed {
+					t.Reject(&ErrAttemptsFailed{Errors: errs})
+					return
+				}
+				timer := c.NewTimer(delay)
+				select {
+				case <-*t.Stopper:
+					timer.Stop()
+					t.Reject(&ErrAttemptsFailed{Errors: errs})
+					return
+				case <-timer.C():
+				}
+			}
+		}
+	})
+}
+
+// ErrAttemptsFailed is returned when every attempt rejected,
+// it records the error of every attempt in the order they were made.
+type ErrAttemptsFailed struct {
+	Errors []error
+}
+
+func (e *ErrAttemptsFailed) Error() string {
+	return fmt.Sprintf("retry: %d attempt(s) failed, last error: %v", len(e.Errors), e.Errors[len(e.Errors)-1])
+}
+
+// Unwrap returns the error of the last attempt.
+func (e *ErrAttemptsFailed) Unwrap() error {
+	return e.Errors[len(e.Errors)-1]
+}
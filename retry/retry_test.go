@@ -0,0 +1,162 @@
+package retry_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/clock/fake"
+	"github.com/brad-jones/goasync/v2/retry"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/stretchr/testify/assert"
+)
+
+var errTemporary = errors.New("temporary")
+var errPermanent = errors.New("permanent")
+
+// failing returns a factory whose first n attempts reject with the given errors
+func failing(attempts *int, errs ...error) func() *task.Task {
+	return func() *task.Task {
+		*attempts++
+		if *attempts <= len(errs) {
+			return task.Rejected(errs[*attempts-1])
+		}
+		return task.Resolved(*attempts)
+	}
+}
+
+func TestDo(t *testing.T) {
+	c := fake.New(time.Now())
+	attempts := 0
+	r := retry.DoAsync(retry.Options{
+		Backoff: retry.Constant(time.Second),
+		Clock:   c,
+	}, failing(&attempts, errTemporary, errTemporary))
+
+	for i := 0; i < 2; i++ {
+		c.BlockUntil(1)
+		c.Advance(time.Second)
+	}
+
+	v, err := r.Result()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, v)
+}
+
+func TestMaxAttempts(t *testing.T) {
+	attempts := 0
+	_, err := retry.Do(retry.Options{MaxAttempts: 3}, failing(&attempts, errTemporary, errTemporary, errPermanent, errTemporary))
+	assert.Equal(t, 3, attempts)
+
+	var failed *retry.ErrAttemptsFailed
+	if assert.ErrorAs(t, err, &failed) {
+		if assert.Len(t, failed.Errors, 3) {
+			assert.ErrorIs(t, failed.Errors[0], errTemporary)
+			assert.ErrorIs(t, failed.Errors[2], errPermanent)
+		}
+		assert.ErrorIs(t, failed, errPermanent)
+		assert.Contains(t, failed.Error(), "3 attempt(s) failed")
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	attempts := 0
+	_, err := retry.Do(retry.Options{
+		Retryable: func(err error) bool { return !errors.Is(err, errPermanent) },
+	}, failing(&attempts, errTemporary, errPermanent, errTemporary))
+	assert.Equal(t, 2, attempts)
+
+	var failed *retry.ErrAttemptsFailed
+	if assert.ErrorAs(t, err, &failed) {
+		assert.Len(t, failed.Errors, 2)
+	}
+}
+
+func TestStopDuringBackoff(t *testing.T) {
+	c := fake.New(time.Now())
+	attempts := 0
+	r := retry.DoAsync(retry.Options{
+		Backoff: retry.Constant(time.Hour),
+		Clock:   c,
+	}, failing(&attempts, errTemporary, errTemporary))
+
+	c.BlockUntil(1)
+	r.Stop()
+	assert.Equal(t, 1, attempts)
+	assert.Equal(t, 0, c.Waiters())
+
+	var failed *retry.ErrAttemptsFailed
+	if assert.ErrorAs(t, r.Wait(), &failed) {
+		assert.Len(t, failed.Errors, 1)
+	}
+}
+
+func TestStopDuringAttempt(t *testing.T) {
+	running := make(chan struct{})
+	var attempt *task.Task
+	r := retry.DoAsync(retry.Options{}, func() *task.Task {
+		attempt = task.New(func(t *task.Internal) {
+			close(running)
+			<-*t.Stopper
+		})
+		return attempt
+	})
+
+	<-running
+	r.Stop()
+	assert.Equal(t, task.StateStopped, attempt.State())
+
+	var failed *retry.ErrAttemptsFailed
+	var stopped *task.ErrTaskStopped
+	if assert.ErrorAs(t, r.Wait(), &failed) {
+		assert.ErrorAs(t, failed, &stopped)
+	}
+}
+
+func TestExponential(t *testing.T) {
+	b := retry.Exponential(time.Second, time.Minute, 2)
+	assert.Equal(t, time.Second, b.Delay(1, 0))
+	assert.Equal(t, 2*time.Second, b.Delay(2, 0))
+	assert.Equal(t, 32*time.Second, b.Delay(6, 0))
+	assert.Equal(t, time.Minute, b.Delay(7, 0))
+
+	// Without a cap the delay does not overflow
+	unbounded := retry.Exponential(time.Second, 0, 2)
+	assert.Equal(t, time.Duration(math.MaxInt64), unbounded.Delay(40, 0))
+	assert.Equal(t, time.Duration(math.MaxInt64), unbounded.Delay(10000, 0))
+}
+
+func TestJittered(t *testing.T) {
+	b := retry.Jittered(retry.Constant(time.Second), 0.5)
+	for i := 0; i < 100; i++ {
+		d := b.Delay(1, 0)
+		assert.GreaterOrEqual(t, int64(d), int64(500*time.Millisecond))
+		assert.LessOrEqual(t, int64(d), int64(1500*time.Millisecond))
+	}
+
+	huge := retry.Jittered(retry.Constant(math.MaxInt64), 0.5)
+	for i := 0; i < 100; i++ {
+		assert.Greater(t, int64(huge.Delay(1, 0)), int64(0))
+	}
+}
+
+func TestDecorrelated(t *testing.T) {
+	b := retry.Decorrelated(time.Second, time.Minute)
+	previous := time.Duration(0)
+	for retry := 1; retry <= 100; retry++ {
+		d := b.Delay(retry, previous)
+		assert.GreaterOrEqual(t, int64(d), int64(time.Second))
+		assert.LessOrEqual(t, int64(d), int64(time.Minute))
+		if previous > 0 {
+			assert.LessOrEqual(t, int64(d), int64(3*previous))
+		}
+		previous = d
+	}
+
+	// Without a cap the delay does not overflow
+	unbounded := retry.Decorrelated(time.Second, 0)
+	for _, previous := range []time.Duration{math.MaxInt64 / 2, math.MaxInt64} {
+		assert.GreaterOrEqual(t, int64(unbounded.Delay(50, previous)), int64(time.Second))
+	}
+}
@@ -0,0 +1,226 @@
+// Package shutdown gracefully stops long running tasks when a process is asked to exit.
+package shutdown
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/stop"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/brad-jones/goerr/v2"
+)
+
+// Options configures a Manager.
+type Options struct {
+	// The signals that start a shutdown, defaults to SIGINT & SIGTERM.
+	Signals []os.Signal
+
+	// How long each phase is given to stop, unless the phase was declared
+	// with it's own timeout. Defaults to 10 seconds.
+	Timeout time.Duration
+}
+
+// Manager stops registered tasks in phases, create new instances with New.
+//
+// Phases are stopped in the reverse order they were first registered, so a
+// daemon that registers "db", then "workers", then "http" will stop accepting
+// http requests before the workers are stopped & the workers are stopped
+// before the database connections are closed.
+//
+// The tasks of a phase are all stopped at once. A second signal received
+// during a shutdown escalates it, the remaining tasks are told to stop but
+// are no longer waited for.
+type Manager struct {
+	options Options
+
+	// Protects phases & started
+	mu      sync.Mutex
+	phases  []*phase
+	started bool
+
+	once    sync.Once
+	trigger chan struct{}
+	done    chan struct{}
+	err     error
+}
+
+type phase struct {
+	name    string
+	timeout time.Duration
+	names   []string
+	tasks   []*task.Task
+}
+
+// Failure records a task that did not stop in time.
+type Failure struct {
+	Phase string
+	Name  string
+	Err   error
+}
+
+// New creates a new Manager.
+func New(options Options) *Manager {
+	if len(options.Signals) == 0 {
+		options.Signals = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = 10 * time.Second
+	}
+	return &Manager{
+		options: options,
+		trigger: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Phase declares a phase with it's own timeout. Phases that are not declared
+// are created with the default timeout the first time they are registered to.
+func (m *Manager) Phase(name string, timeout time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.phase(name).timeout = timeout
+}
+
+// Register adds a named task to a phase. Tasks registered once
+// a shutdown has started are stopped straight away.
+func (m *Manager) Register(phase string, name string, t *task.Task) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.started {
+		stop.AllAsync(t)
+		return
+	}
+	p := m.phase(phase)
+	p.names = append(p.names, name)
+	p.tasks = append(p.tasks, t)
+}
+
+// Wait blocks until one of the configured signals is received or Shutdown is
+// called, it then returns once the shutdown has finished. If any task failed
+// to stop an ErrShutdownFailed is returned.
+func (m *Manager) Wait() error {
+	signals := m.notify()
+	defer signal.Stop(signals)
+
+	select {
+	case <-signals:
+		m.shutdown(signals)
+	case <-m.trigger:
+	}
+
+	<-m.done
+	return m.err
+}
+
+// Shutdown stops all registered tasks now, without waiting for a signal.
+// It is safe to call many times, every call returns the same result.
+func (m *Manager) Shutdown() error {
+	signals := m.notify()
+	defer signal.Stop(signals)
+	m.shutdown(signals)
+	return m.err
+}
+
+// notify starts listening for the configured signals
+func (m *Manager) notify() chan os.Signal {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, m.options.Signals...)
+	return signals
+}
+
+// shutdown runs the shutdown once, blocking until it has finished
+func (m *Manager) shutdown(signals <-chan os.Signal) {
+	m.once.Do(func() {
+		close(m.trigger)
+		m.err = m.run(signals)
+		close(m.done)
+	})
+}
+
+// run stops each phase in reverse order, a signal escalates the shutdown
+func (m *Manager) run(signals <-chan os.Signal) error {
+	m.mu.Lock()
+	m.started = true
+	phases := m.phases
+	m.mu.Unlock()
+
+	failures := []*Failure{}
+	escalated := false
+
+	for i := len(phases) - 1; i >= 0; i-- {
+		p := phases[i]
+
+		if escalated {
+			stop.AllConcurrentlyAsync(p.tasks...)
+			for _, name := range p.names {
+				failures = append(failures, &Failure{p.name, name, goerr.Wrap(&ErrEscalated{})})
+			}
+			continue
+		}
+
+		stopped := make(chan error, 1)
+		go func() {
+			stopped <- stop.AllConcurrentlyWithTimeout(p.timeout, p.tasks...)
+		}()
+
+		select {
+		case err := <-stopped:
+			var stopFailed *stop.ErrStopFailed
+			if errors.As(err, &stopFailed) {
+				for j, index := range stopFailed.Indexes {
+					failures = append(failures, &Failure{p.name, p.names[index], stopFailed.Errors[j]})
+				}
+			}
+		case <-signals:
+			escalated = true
+			for j, t := range p.tasks {
+				if !t.IsCompleted() {
+					failures = append(failures, &Failure{p.name, p.names[j], goerr.Wrap(&ErrEscalated{})})
+				}
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		return goerr.Wrap(&ErrShutdownFailed{Failures: failures, Escalated: escalated})
+	}
+	return nil
+}
+
+// phase returns the named phase, creating it if needed. The caller must hold mu.
+func (m *Manager) phase(name string) *phase {
+	for _, p := range m.phases {
+		if p.name == name {
+			return p
+		}
+	}
+	p := &phase{name: name, timeout: m.options.Timeout}
+	m.phases = append(m.phases, p)
+	return p
+}
+
+// ErrShutdownFailed is returned when at least one task failed to stop.
+type ErrShutdownFailed struct {
+	Failures []*Failure
+
+	// True if a second signal cut the shutdown short
+	Escalated bool
+}
+
+func (e *ErrShutdownFailed) Error() string {
+	return fmt.Sprintf("shutdown: %d tasks failed to stop", len(e.Failures))
+}
+
+// ErrEscalated is the error of a task that was still being
+// waited for when a shutdown was escalated.
+type ErrEscalated struct {
+}
+
+func (e *ErrEscalated) Error() string {
+	return "shutdown: stopped waiting for the task because the shutdown was escalated"
+}
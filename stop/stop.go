@@ -2,9 +2,13 @@
 package stop
 
 import (
+	"fmt"
+	"sync"
 	"time"
 
+	"github.com/brad-jones/goasync/v2/clock"
 	"github.com/brad-jones/goasync/v2/task"
+	"github.com/brad-jones/goerr/v2"
 )
 
 // All will loop through all provided objects and call their Stop method.
@@ -35,3 +39,88 @@ func AllWithTimeoutAsync(timeout time.Duration, stopables ...*task.Task) *task.T
 		AllWithTimeout(timeout, stopables...)
 	})
 }
+
+// AllConcurrently does the same thing as All but signals every task to stop
+// at once, rather than one after another, & then waits for them all to stop.
+func AllConcurrently(stopables ...*task.Task) {
+	wg := sync.WaitGroup{}
+	for _, stopable := range stopables {
+		wg.Add(1)
+		go func(stopable *task.Task) {
+			defer wg.Done()
+			stopable.Stop()
+		}(stopable)
+	}
+	wg.Wait()
+}
+
+// AllConcurrentlyAsync does exactly the same thing as AllConcurrently but does so asynchronously.
+func AllConcurrentlyAsync(stopables ...*task.Task) *task.Task {
+	return task.New(func(t *task.Internal) {
+		AllConcurrently(stopables...)
+	})
+}
+
+// AllConcurrentlyWithTimeout signals every task to stop at once & waits until
+// either they have all stopped or the timeout is reached, so the timeout is
+// an overall deadline rather than a per task timeout. An ErrStopFailed that
+// lists the tasks that did not stop in time is returned.
+func AllConcurrentlyWithTimeout(timeout time.Duration, stopables ...*task.Task) error {
+	for _, stopable := range stopables {
+		go stopable.Stop()
+	}
+
+	timer := clock.Default().NewTimer(timeout)
+	defer timer.Stop()
+
+wait:
+	for _, stopable := range stopables {
+		select {
+		case <-*stopable.Done:
+		case <-timer.C():
+			break wait
+		}
+	}
+
+	failedErrors := []error{}
+	failedIndexes := []int{}
+	for i, stopable := range stopables {
+		select {
+		case <-*stopable.Done:
+		default:
+			failedErrors = append(failedErrors, goerr.Wrap(&task.ErrStoppingTaskTimeout{}))
+			failedIndexes = append(failedIndexes, i)
+		}
+	}
+	if len(failedErrors) > 0 {
+		return goerr.Wrap(&ErrStopFailed{
+			Errors:  failedErrors,
+			Indexes: failedIndexes,
+		})
+	}
+
+	return nil
+}
+
+// AllConcurrentlyWithTimeoutAsync does exactly the same thing as
+// AllConcurrentlyWithTimeout but does so asynchronously.
+func AllConcurrentlyWithTimeoutAsync(timeout time.Duration, stopables ...*task.Task) *task.Task {
+	return task.New(func(t *task.Internal) {
+		if err := AllConcurrentlyWithTimeout(timeout, stopables...); err != nil {
+			t.Reject(err)
+		}
+	})
+}
+
+// ErrStopFailed is returned by AllConcurrentlyWithTimeout
+// when at least one task did not stop in time.
+type ErrStopFailed struct {
+	Errors []error
+
+	// The index of the task that failed to stop for each error, in the same order as Errors.
+	Indexes []int
+}
+
+func (e *ErrStopFailed) Error() string {
+	return fmt.Sprintf("stop: %d tasks failed to stop in time", len(e.Errors))
+}
@@ -0,0 +1,110 @@
+package stop_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/clock"
+	"github.com/brad-jones/goasync/v2/clock/fake"
+	"github.com/brad-jones/goasync/v2/stop"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/stretchr/testify/assert"
+)
+
+// cooperative returns a task that stops as soon as it is told to
+func cooperative() *task.Task {
+	return task.New(func(t *task.Internal) {
+		<-*t.Stopper
+	})
+}
+
+// stubborn returns a task that ignores being told to stop until released
+func stubborn(release chan struct{}) *task.Task {
+	return task.New(func(t *task.Internal) {
+		<-release
+	})
+}
+
+func TestAll(t *testing.T) {
+	var mu sync.Mutex
+	order := []int{}
+	tasks := []*task.Task{}
+	for i := 0; i < 3; i++ {
+		i := i
+		tasks = append(tasks, task.New(func(t *task.Internal) {
+			<-*t.Stopper
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, i)
+		}))
+	}
+
+	stop.All(tasks...)
+	assert.Equal(t, []int{0, 1, 2}, order)
+	for _, tk := range tasks {
+		assert.True(t, tk.IsCompleted())
+	}
+}
+
+func TestAllConcurrently(t *testing.T) {
+	// Each task only returns once the other has been told to stop,
+	// so stopping them one after another would never return.
+	var a, b *task.Task
+	created := make(chan struct{})
+	a = task.New(func(t *task.Internal) {
+		<-created
+		<-*t.Stopper
+		<-*b.Stopper
+	})
+	b = task.New(func(t *task.Internal) {
+		<-created
+		<-*t.Stopper
+		<-*a.Stopper
+	})
+	close(created)
+
+	done := stop.AllConcurrentlyAsync(a, b)
+	select {
+	case <-*done.Done:
+	case <-time.After(time.Second):
+		t.Fatal("timed out stopping the tasks")
+	}
+	assert.Equal(t, task.StateStopped, a.State())
+	assert.Equal(t, task.StateStopped, b.State())
+}
+
+func TestAllConcurrentlyWithTimeout(t *testing.T) {
+	assert.NoError(t, stop.AllConcurrentlyWithTimeout(time.Second, cooperative(), cooperative()))
+}
+
+func TestAllConcurrentlyWithTimeoutFailed(t *testing.T) {
+	c := fake.New(time.Now())
+	defer clock.SetDefault(clock.SetDefault(c))
+
+	release := make(chan struct{})
+	defer close(release)
+
+	first, last := cooperative(), cooperative()
+	r := stop.AllConcurrentlyWithTimeoutAsync(5*time.Second,
+		first, stubborn(release), last, stubborn(release),
+	)
+
+	// Every task shares one deadline rather than each having it's own timer
+	c.BlockUntil(1)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 1, c.Waiters())
+	c.Advance(5 * time.Second)
+
+	_, err := r.Result()
+	var failed *stop.ErrStopFailed
+	if assert.ErrorAs(t, err, &failed) {
+		assert.Equal(t, []int{1, 3}, failed.Indexes)
+		if assert.Len(t, failed.Errors, 2) {
+			assert.ErrorAs(t, failed.Errors[0], new(*task.ErrStoppingTaskTimeout))
+		}
+	}
+	assert.EqualError(t, err, "stop: 2 tasks failed to stop in time")
+	assert.Equal(t, task.StateStopped, first.State())
+	assert.Equal(t, task.StateStopped, last.State())
+}
@@ -0,0 +1,229 @@
+// Package supervisor restarts long running tasks when they fail.
+package supervisor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/clock"
+	"github.com/brad-jones/goasync/v2/stop"
+	"github.com/brad-jones/goasync/v2/task"
+)
+
+// Strategy decides which children are restarted when a child fails.
+type Strategy int
+
+const (
+	// OneForOne restarts only the child that failed.
+	OneForOne Strategy = iota
+
+	// OneForAll stops all other children & then restarts every child.
+	OneForAll
+
+	// RestForOne stops the children started after the child that failed &
+	// then restarts the failed child along with them.
+	RestForOne
+)
+
+// Restart decides when a child is restarted.
+type Restart int
+
+const (
+	// Transient children are restarted when they reject or panic.
+	Transient Restart = iota
+
+	// Permanent children are always restarted, even when they resolve.
+	Permanent
+
+	// Temporary children are never restarted.
+	Temporary
+)
+
+// Child describes a task that is supervised.
+type Child struct {
+	// Used to identify the child in errors & events
+	Name string
+
+	// Creates the child's task, it is called again each time the child is restarted
+	Start func() *task.Task
+
+	// When to restart the child, defaults to Transient
+	Restart Restart
+}
+
+// Options configures a Supervisor.
+type Options struct {
+	// Which children to restart when a child fails, defaults to OneForOne.
+	Strategy Strategy
+
+	// The maximum number of restarts allowed within Period, once exceeded
+	// the supervisor stops all children & rejects with an ErrTooManyRestarts.
+	// Defaults to 3, a negative number allows no restarts at all so the first
+	// child that would be restarted causes the supervisor to reject.
+	MaxRestarts int
+
+	// Defaults to 5 seconds.
+	Period time.Duration
+
+	// Called each time a child is restarted because it exited,
+	// err is nil for a Permanent child that resolved.
+	OnRestart func(name string, err error)
+
+	// Used to measure the restart intensity, defaults to clock.Default()
+	Clock clock.Clock
+}
+
+// Supervisor starts a set of children & restarts them according to it's
+// strategy, much like an Erlang supervisor. Create new instances with New.
+//
+// The supervisor itself is a task, stopping it stops all children in the
+// reverse order they were started. It completes, without resolving a value,
+// once every child has exited without being restarted.
+//
+// Supervisors can be nested by starting one supervisor as the child of
+// another. When a supervisor exceeds it's restart intensity it rejects, so
+// the failure escalates to the parent which applies it's own strategy.
+type Supervisor struct {
+	*task.Task
+}
+
+// New creates a supervisor that starts the given children in order.
+func New(options Options, children ...*Child) *Supervisor {
+	if options.MaxRestarts == 0 {
+		options.MaxRestarts = 3
+	}
+	if options.Period <= 0 {
+		options.Period = 5 * time.Second
+	}
+	options.Clock = clock.Or(options.Clock)
+
+	return &Supervisor{Task: task.New(func(t *task.Internal) {
+		supervise(t, options, children)
+	})}
+}
+
+// supervise is the body of the supervisor task
+func supervise(t *task.Internal, options Options, children []*Child) {
+	type exit struct {
+		index int
+		child *task.Task
+	}
+
+	finished := make(chan struct{})
+	defer close(finished)
+	exited := make(chan exit)
+
+	running := make([]*task.Task, len(children))
+	restarts := []time.Time{}
+
+	start := func(i int) {
+		child := children[i].Start()
+		running[i] = child
+		go func() {
+			<-*child.Done
+			select {
+			case exited <- exit{i, child}:
+			case <-finished:
+			}
+		}()
+	}
+
+	// stopFrom stops every running child from index i onwards, in reverse order
+	stopFrom := func(i int) {
+		stopables := []*task.Task{}
+		for j := len(running) - 1; j >= i; j-- {
+			if running[j] != nil {
+				stopables = append(stopables, running[j])
+			}
+		}
+		stop.All(stopables...)
+	}
+
+	for i := range children {
+		start(i)
+	}
+
+	for {
+		alive := false
+		for _, child := range running {
+			if child != nil {
+				alive = true
+			}
+		}
+		if !alive {
+			return
+		}
+
+		select {
+		case <-*t.Stopper:
+			stopFrom(0)
+			return
+		case e := <-exited:
+			// Children that were stopped to be restarted have already been replaced
+			if running[e.index] != e.child {
+				continue
+			}
+
+			_, err := e.child.Result()
+			restart := children[e.index].Restart
+			if restart == Temporary || (restart == Transient && err == nil) {
+				running[e.index] = nil
+				continue
+			}
+
+			now := options.Clock.Now()
+			restarts = append(restarts, now)
+			for len(restarts) > 0 && now.Sub(restarts[0]) > options.Period {
+				restarts = restarts[1:]
+			}
+			if len(restarts) > options.MaxRestarts {
+				running[e.index] = nil
+				stopFrom(0)
+				t.Reject(&ErrTooManyRestarts{Child: children[e.index].Name, Err: err})
+				return
+			}
+
+			if options.OnRestart != nil {
+				options.OnRestart(children[e.index].Name, err)
+			}
+
+			first := e.index
+			switch options.Strategy {
+			case OneForAll:
+				first = 0
+			case RestForOne:
+				first = e.index
+			default:
+				start(e.index)
+				continue
+			}
+
+			// Children that have already exited for good stay that way
+			running[e.index] = nil
+			stopFrom(first)
+			for i := first; i < len(children); i++ {
+				if i == e.index || running[i] != nil {
+					start(i)
+				}
+			}
+		}
+	}
+}
+
+// ErrTooManyRestarts is returned when children have been restarted
+// more often than the supervisor's restart intensity allows.
+type ErrTooManyRestarts struct {
+	// The name of the child whose failure exceeded the limit
+	Child string
+
+	// The error of that failure, nil if a Permanent child resolved
+	Err error
+}
+
+func (e *ErrTooManyRestarts) Error() string {
+	return fmt.Sprintf("supervisor: too many restarts, child %q exited with: %v", e.Child, e.Err)
+}
+
+func (e *ErrTooManyRestarts) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,264 @@
+package supervisor_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/clock/fake"
+	"github.com/brad-jones/goasync/v2/supervisor"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/stretchr/testify/assert"
+)
+
+var errBoom = errors.New("boom")
+
+// child returns a supervised child along with a channel that receives
+// the source of each run, so the test decides how every run exits
+func child(name string, restart supervisor.Restart) (*supervisor.Child, chan *task.Source) {
+	runs := make(chan *task.Source, 10)
+	return &supervisor.Child{
+		Name:    name,
+		Restart: restart,
+		Start: func() *task.Task {
+			s := task.NewSource()
+			runs <- s
+			return s.Task()
+		},
+	}, runs
+}
+
+// next waits for the next run of a child to start
+func next(t *testing.T, runs chan *task.Source) *task.Source {
+	t.Helper()
+	select {
+	case s := <-runs:
+		return s
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the child to start")
+		return nil
+	}
+}
+
+// idle asserts a child is not started again
+func idle(t *testing.T, runs chan *task.Source) {
+	t.Helper()
+	select {
+	case <-runs:
+		t.Fatal("child was restarted")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestOneForOne(t *testing.T) {
+	a, aRuns := child("a", supervisor.Transient)
+	b, bRuns := child("b", supervisor.Transient)
+
+	restarted := make(chan string, 10)
+	s := supervisor.New(supervisor.Options{
+		OnRestart: func(name string, err error) {
+			assert.ErrorIs(t, err, errBoom)
+			restarted <- name
+		},
+	}, a, b)
+	defer s.Stop()
+
+	a1, b1 := next(t, aRuns), next(t, bRuns)
+	a1.Reject(errBoom)
+	next(t, aRuns)
+	assert.Equal(t, "a", <-restarted)
+	assert.Equal(t, task.StateRunning, b1.Task().State())
+	idle(t, bRuns)
+}
+
+func TestOneForAll(t *testing.T) {
+	a, aRuns := child("a", supervisor.Transient)
+	b, bRuns := child("b", supervisor.Transient)
+	c, cRuns := child("c", supervisor.Transient)
+	s := supervisor.New(supervisor.Options{Strategy: supervisor.OneForAll}, a, b, c)
+	defer s.Stop()
+
+	a1, b1, c1 := next(t, aRuns), next(t, bRuns), next(t, cRuns)
+	b1.Reject(errBoom)
+	next(t, aRuns)
+	next(t, bRuns)
+	next(t, cRuns)
+	assert.Equal(t, task.StateStopped, a1.Task().State())
+	assert.Equal(t, task.StateStopped, c1.Task().State())
+}
+
+func TestRestForOne(t *testing.T) {
+	a, aRuns := child("a", supervisor.Transient)
+	b, bRuns := child("b", supervisor.Transient)
+	c, cRuns := child("c", supervisor.Transient)
+	s := supervisor.New(supervisor.Options{Strategy: supervisor.RestForOne}, a, b, c)
+	defer s.Stop()
+
+	a1, b1, c1 := next(t, aRuns), next(t, bRuns), next(t, cRuns)
+	b1.Reject(errBoom)
+	next(t, bRuns)
+	next(t, cRuns)
+	assert.Equal(t, task.StateStopped, c1.Task().State())
+	assert.Equal(t, task.StateRunning, a1.Task().State())
+	idle(t, aRuns)
+}
+
+func TestRestartIntensity(t *testing.T) {
+	c := fake.New(time.Now())
+	a, aRuns := child("a", supervisor.Transient)
+	b, bRuns := child("b", supervisor.Transient)
+	s := supervisor.New(supervisor.Options{
+		MaxRestarts: 2,
+		Period:      time.Minute,
+		Clock:       c,
+	}, a, b)
+
+	b1 := next(t, bRuns)
+
+	// Two restarts within the period are allowed
+	next(t, aRuns).Reject(errBoom)
+	next(t, aRuns).Reject(errBoom)
+	a3 := next(t, aRuns)
+
+	// Once the period has passed the earlier restarts no longer count
+	c.Advance(2 * time.Minute)
+	a3.Reject(errBoom)
+	next(t, aRuns).Reject(errBoom)
+
+	// The third restart within the period is one too many
+	next(t, aRuns).Reject(errBoom)
+
+	_, err := s.Result()
+	var tooMany *supervisor.ErrTooManyRestarts
+	if assert.ErrorAs(t, err, &tooMany) {
+		assert.Equal(t, "a", tooMany.Child)
+	}
+	assert.ErrorIs(t, err, errBoom)
+	assert.Equal(t, task.StateRejected, s.State())
+	assert.Equal(t, task.StateStopped, b1.Task().State())
+	idle(t, aRuns)
+}
+
+func TestNegativeMaxRestarts(t *testing.T) {
+	a, aRuns := child("a", supervisor.Transient)
+	s := supervisor.New(supervisor.Options{MaxRestarts: -1}, a)
+
+	next(t, aRuns).Reject(errBoom)
+
+	_, err := s.Result()
+	var tooMany *supervisor.ErrTooManyRestarts
+	assert.ErrorAs(t, err, &tooMany)
+	idle(t, aRuns)
+}
+
+func TestPermanent(t *testing.T) {
+	a, aRuns := child("a", supervisor.Permanent)
+
+	restarted := make(chan error, 10)
+	s := supervisor.New(supervisor.Options{
+		OnRestart: func(name string, err error) { restarted <- err },
+	}, a)
+	defer s.Stop()
+
+	next(t, aRuns).Resolve("done")
+	next(t, aRuns)
+	assert.NoError(t, <-restarted)
+}
+
+func TestTemporaryAndTransient(t *testing.T) {
+	a, aRuns := child("a", supervisor.Temporary)
+	b, bRuns := child("b", supervisor.Transient)
+	s := supervisor.New(supervisor.Options{}, a, b)
+
+	a1, b1 := next(t, aRuns), next(t, bRuns)
+
+	// Temporary children are not restarted, even when they fail
+	a1.Reject(errBoom)
+	idle(t, aRuns)
+
+	// Transient children that resolve are not restarted either
+	b1.Resolve("done")
+	idle(t, bRuns)
+
+	// With no children left the supervisor completes
+	v, err := s.Result()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+	assert.Equal(t, task.StateCompleted, s.State())
+}
+
+func TestStop(t *testing.T) {
+	var mu sync.Mutex
+	stopped := []string{}
+	children := []*supervisor.Child{}
+	for _, name := range []string{"a", "b", "c"} {
+		name := name
+		children = append(children, &supervisor.Child{
+			Name: name,
+			Start: func() *task.Task {
+				return task.New(func(t *task.Internal) {
+					<-*t.Stopper
+					mu.Lock()
+					defer mu.Unlock()
+					stopped = append(stopped, name)
+				})
+			},
+		})
+	}
+
+	s := supervisor.New(supervisor.Options{}, children...)
+	s.Stop()
+	assert.Equal(t, task.StateStopped, s.State())
+	assert.Equal(t, []string{"c", "b", "a"}, stopped)
+}
+
+func TestEscalation(t *testing.T) {
+	leaf, leafRuns := child("leaf", supervisor.Transient)
+	inner := &supervisor.Child{
+		Name: "inner",
+		Start: func() *task.Task {
+			return supervisor.New(supervisor.Options{MaxRestarts: -1}, leaf).Task
+		},
+	}
+
+	restarted := make(chan error, 10)
+	s := supervisor.New(supervisor.Options{
+		MaxRestarts: 1,
+		OnRestart:   func(name string, err error) { restarted <- err },
+	}, inner)
+	defer s.Stop()
+
+	// The inner supervisor gives up straight away & the outer one restarts it
+	next(t, leafRuns).Reject(errBoom)
+	next(t, leafRuns)
+	var tooMany *supervisor.ErrTooManyRestarts
+	if assert.ErrorAs(t, <-restarted, &tooMany) {
+		assert.Equal(t, "leaf", tooMany.Child)
+	}
+}
+
+func TestEscalationExceedsParent(t *testing.T) {
+	leaf, leafRuns := child("leaf", supervisor.Transient)
+	inner := &supervisor.Child{
+		Name: "inner",
+		Start: func() *task.Task {
+			return supervisor.New(supervisor.Options{MaxRestarts: -1}, leaf).Task
+		},
+	}
+	s := supervisor.New(supervisor.Options{MaxRestarts: 1}, inner)
+
+	next(t, leafRuns).Reject(errBoom)
+	next(t, leafRuns).Reject(errBoom)
+
+	_, err := s.Result()
+	var outer *supervisor.ErrTooManyRestarts
+	if assert.ErrorAs(t, err, &outer) {
+		assert.Equal(t, "inner", outer.Child)
+		var nested *supervisor.ErrTooManyRestarts
+		if assert.ErrorAs(t, outer.Err, &nested) {
+			assert.Equal(t, "leaf", nested.Child)
+		}
+	}
+	assert.ErrorIs(t, err, errBoom)
+}
@@ -0,0 +1,87 @@
+package task_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/stretchr/testify/assert"
+)
+
+// isDone reports if the task finishes within a little while
+func isDone(t *task.Task) bool {
+	select {
+	case <-*t.Done:
+		return true
+	case <-time.After(50 * time.Millisecond):
+		return false
+	}
+}
+
+func TestSpawnStoppedWithParent(t *testing.T) {
+	var child *task.Task
+	parent := task.New(func(t *task.Internal) {
+		child = t.Spawn(func(t *task.Internal) {
+			<-*t.Stopper
+		})
+	})
+
+	// The parent's function has returned but it is not done until it's child is
+	assert.False(t, isDone(parent))
+
+	parent.Stop()
+	assert.Equal(t, task.StateStopped, child.State())
+	assert.True(t, parent.IsCompleted())
+}
+
+func TestAdoptStoppedWithParent(t *testing.T) {
+	adopted := task.New(func(t *task.Internal) {
+		<-*t.Stopper
+	})
+	parent := task.New(func(t *task.Internal) {
+		t.Adopt(adopted)
+		<-*t.Stopper
+	})
+
+	parent.Stop()
+	assert.Equal(t, task.StateStopped, adopted.State())
+}
+
+func TestParentWaitsForChildren(t *testing.T) {
+	gate := make(chan struct{})
+	var child *task.Task
+	parent := task.New(func(t *task.Internal) {
+		child = t.Spawn(func() { <-gate })
+		t.Resolve("parent")
+	})
+
+	assert.False(t, isDone(parent))
+	close(gate)
+	v, err := parent.Result()
+	assert.NoError(t, err)
+	assert.Equal(t, "parent", v)
+	assert.True(t, child.IsCompleted())
+}
+
+func TestParentWaitsForChildrenAdoptedWhileWaiting(t *testing.T) {
+	first, second := make(chan struct{}), make(chan struct{})
+	adopted := make(chan *task.Task, 1)
+
+	parent := task.New(func(t *task.Internal) {
+		// The first child adopts another child into the parent after the
+		// parent's function has returned & it is waiting for it's children
+		t.Spawn(func() {
+			<-first
+			adopted <- t.Adopt(task.New(func() { <-second }))
+		})
+	})
+
+	assert.False(t, isDone(parent))
+	close(first)
+	late := <-adopted
+	assert.False(t, isDone(parent))
+
+	close(second)
+	assert.NoError(t, parent.Wait())
+	assert.True(t, late.IsCompleted())
+}
@@ -0,0 +1,96 @@
+package task
+
+import (
+	"context"
+
+	"github.com/brad-jones/goerr/v2"
+)
+
+// Catch registers a callback to be called when this Task rejects.
+// Accepts `func(err error) interface{}`, `func(err error) *Task`
+// or `func(err error, t *Internal)`
+//
+// The first returns a fallback value that the new task resolves, the second
+// returns a new task that the new task will follow & the third allows the
+// callback to resolve or reject the new task itself. If this task does not
+// reject then the new task simply follows this task.
+//
+// The callback is called even if this task was stopped, in which
+// case it is given an ErrTaskStopped.
+func (t *Task) Catch(fn interface{}) *Task {
+	return t.derive(func(t2 *Internal) {
+		_, err := t.Result()
+		if err == nil {
+			t2.follow(t)
+			return
+		}
+		switch fn := fn.(type) {
+		case func(err error) interface{}:
+			t2.Resolve(fn(err))
+		case func(err error) *Task:
+			t2.follow(t2.Adopt(fn(err)))
+		case func(err error, t *Internal):
+			fn(err, t2)
+		}
+	})
+}
+
+// Finally registers a callback that is always called when this Task
+// completes, regardless of the outcome, even if it was stopped.
+// The new task follows this task.
+func (t *Task) Finally(fn func()) *Task {
+	return t.derive(func(t2 *Internal) {
+		<-*t.Done
+		fn()
+		t2.follow(t)
+	})
+}
+
+// Map registers a callback that transforms the result of this Task.
+// If this task rejects then the callback is not called and the new task
+// rejects with the same error.
+func (t *Task) Map(fn func(result interface{}) (interface{}, error)) *Task {
+	return t.derive(func(t2 *Internal) {
+		v, err := t.Result()
+		if err != nil {
+			t2.Reject(err)
+			return
+		}
+		t2.Settle(fn(v))
+	})
+}
+
+// FlatMap registers a callback that returns a new task based on the result
+// of this Task, the new task returned by FlatMap will follow that task.
+// If this task rejects then the callback is not called and the new task
+// rejects with the same error.
+func (t *Task) FlatMap(fn func(result interface{}) *Task) *Task {
+	return t.derive(func(t2 *Internal) {
+		v, err := t.Result()
+		if err != nil {
+			t2.Reject(err)
+			return
+		}
+		t2.follow(t2.Adopt(fn(v)))
+	})
+}
+
+// derive creates a new task that shares the stopper of this task,
+// stopping either task will stop both.
+func (t *Task) derive(fn func(t *Internal)) *Task {
+	return newTask(context.Background(), t.Stopper, fn)
+}
+
+// follow waits for the given task to complete & then resolves or rejects
+// the same outcome. Tasks that completed without resolving anything cause
+// the following task to also complete without resolving anything.
+func (i *Internal) follow(t *Task) {
+	v, err := t.Result()
+	if err != nil {
+		i.Rejector <- goerr.Wrap(err)
+		return
+	}
+	if t.State() == StateResolved {
+		i.Resolver <- v
+	}
+}
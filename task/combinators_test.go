@@ -0,0 +1,143 @@
+package task_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/stretchr/testify/assert"
+)
+
+// stopped returns a task that has been stopped while it was running
+func stopped() *task.Task {
+	running := make(chan struct{})
+	t := task.New(func(t *task.Internal) {
+		close(running)
+		<-*t.Stopper
+	})
+	<-running
+	t.Stop()
+	return t
+}
+
+func TestThen(t *testing.T) {
+	v, err := task.Resolved(1).Then(func(r interface{}, t *task.Internal) {
+		t.Resolve(r.(int) + 1)
+	}).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, v)
+
+	called := false
+	_, err = task.Rejected(errors.New("boom")).Then(func() { called = true }).Result()
+	assert.EqualError(t, err, "boom")
+	assert.False(t, called)
+
+	var stop *task.ErrTaskStopped
+	_, err = stopped().Then(func() { called = true }).Result()
+	assert.ErrorAs(t, err, &stop)
+	assert.False(t, called)
+}
+
+func TestCatch(t *testing.T) {
+	called := false
+	v, err := task.Resolved(1).Catch(func(err error) interface{} {
+		called = true
+		return 2
+	}).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+	assert.False(t, called)
+
+	v, err = task.Rejected(errors.New("boom")).Catch(func(err error) interface{} {
+		return err.Error() + " caught"
+	}).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, "boom caught", v)
+
+	v, err = task.Rejected(errors.New("boom")).Catch(func(err error) *task.Task {
+		return task.Resolved("fallback")
+	}).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, "fallback", v)
+
+	_, err = task.Rejected(errors.New("boom")).Catch(func(err error, t *task.Internal) {
+		t.Reject(errors.New("still broken"))
+	}).Result()
+	assert.EqualError(t, err, "still broken")
+
+	var caught error
+	v, err = stopped().Catch(func(err error) interface{} {
+		caught = err
+		return "recovered"
+	}).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, "recovered", v)
+	var stop *task.ErrTaskStopped
+	assert.ErrorAs(t, caught, &stop)
+}
+
+func TestFinally(t *testing.T) {
+	calls := 0
+	v, err := task.Resolved(1).Finally(func() { calls++ }).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, 1, calls)
+
+	f := task.Rejected(errors.New("boom")).Finally(func() { calls++ })
+	assert.EqualError(t, f.Wait(), "boom")
+	assert.Equal(t, task.StateRejected, f.State())
+	assert.Equal(t, 2, calls)
+
+	f = task.New(func() {}).Finally(func() { calls++ })
+	assert.NoError(t, f.Wait())
+	assert.Equal(t, task.StateCompleted, f.State())
+	assert.Equal(t, 3, calls)
+
+	var stop *task.ErrTaskStopped
+	f = stopped().Finally(func() { calls++ })
+	assert.ErrorAs(t, f.Wait(), &stop)
+	assert.Equal(t, task.StateStopped, f.State())
+	assert.Equal(t, 4, calls)
+
+	// Stopping the new task stops the source but the callback is still called
+	src := task.New(func(t *task.Internal) { <-*t.Stopper })
+	f = src.Finally(func() { calls++ })
+	f.Stop()
+	assert.Equal(t, task.StateStopped, src.State())
+	assert.Equal(t, 5, calls)
+}
+
+func TestMap(t *testing.T) {
+	double := func(r interface{}) (interface{}, error) { return r.(int) * 2, nil }
+
+	v, err := task.Resolved(2).Map(double).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, 4, v)
+
+	_, err = task.Resolved(2).Map(func(r interface{}) (interface{}, error) {
+		return nil, errors.New("bad value")
+	}).Result()
+	assert.EqualError(t, err, "bad value")
+
+	_, err = task.Rejected(errors.New("boom")).Map(double).Result()
+	assert.EqualError(t, err, "boom")
+
+	var stop *task.ErrTaskStopped
+	_, err = stopped().Map(double).Result()
+	assert.ErrorAs(t, err, &stop)
+}
+
+func TestFlatMap(t *testing.T) {
+	next := func(r interface{}) *task.Task { return task.Resolved(r.(int) + 1) }
+
+	v, err := task.Resolved(1).FlatMap(next).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, v)
+
+	_, err = task.Rejected(errors.New("boom")).FlatMap(next).Result()
+	assert.EqualError(t, err, "boom")
+
+	var stop *task.ErrTaskStopped
+	_, err = stopped().FlatMap(next).Result()
+	assert.ErrorAs(t, err, &stop)
+}
@@ -0,0 +1,106 @@
+package task_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/stretchr/testify/assert"
+)
+
+type ctxKey struct{}
+
+func TestNewWithContextStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	running := make(chan struct{})
+	tk := task.NewWithContext(ctx, func(t *task.Internal) {
+		close(running)
+		<-*t.Stopper
+	})
+
+	<-running
+	cancel()
+	_, err := tk.Result()
+	assert.ErrorAs(t, err, new(*task.ErrTaskStopped))
+	assert.Equal(t, task.StateStopped, tk.State())
+}
+
+func TestNewWithContextAlreadyCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	tk := task.NewWithContext(ctx, func(t *task.Internal) {
+		<-*t.Stopper
+	})
+	assert.Equal(t, task.StateStopped, waitState(tk))
+}
+
+// waitState waits for the task to finish & returns it's final state
+func waitState(tk *task.Task) task.State {
+	<-*tk.Done
+	return tk.State()
+}
+
+func TestCancelableCtxInherits(t *testing.T) {
+	deadline := time.Now().Add(time.Hour)
+	parent, cancel := context.WithDeadline(context.WithValue(context.Background(), ctxKey{}, "value"), deadline)
+	defer cancel()
+
+	ctxs := make(chan context.Context, 1)
+	tk := task.NewWithContext(parent, func(t *task.Internal) {
+		ctx := t.CancelableCtx()
+		ctxs <- ctx
+		<-ctx.Done()
+	})
+
+	ctx := <-ctxs
+	assert.Equal(t, "value", ctx.Value(ctxKey{}))
+	d, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, deadline, d)
+	assert.NoError(t, ctx.Err())
+
+	// Stopping the task cancels the context
+	tk.Stop()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func TestCancelableCtxFromNew(t *testing.T) {
+	ctxs := make(chan context.Context, 1)
+	tk := task.New(func(t *task.Internal) {
+		ctx := t.CancelableCtx()
+		ctxs <- ctx
+		<-ctx.Done()
+	})
+	ctx := <-ctxs
+	_, ok := ctx.Deadline()
+	assert.False(t, ok)
+	tk.Stop()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func TestResultCtxLeavesTaskRunning(t *testing.T) {
+	gate := make(chan struct{})
+	tk := task.New(func(t *task.Internal) {
+		<-gate
+		t.Resolve("done")
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := tk.ResultCtx(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, task.StateRunning, tk.State())
+
+	canceled, cancel2 := context.WithCancel(context.Background())
+	cancel2()
+	assert.ErrorIs(t, tk.WaitCtx(canceled), context.Canceled)
+	assert.Equal(t, task.StateRunning, tk.State())
+
+	// The task carries on & can still be awaited
+	close(gate)
+	v, err := tk.ResultCtx(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "done", v)
+	assert.NoError(t, tk.WaitCtx(context.Background()))
+}
@@ -0,0 +1,140 @@
+package task
+
+import (
+	"iter"
+	"sync"
+)
+
+// Generator is a task that yields many values over time rather than resolving
+// a single value, useful for things like tailing logs or walking paginated
+// APIs. Create new instances with NewGenerator or NewGeneratorWithBuffer.
+//
+// The values are consumed with C, All or a Wait & Value loop. Once the
+// generator function returns, Err will return the final error if any.
+type Generator struct {
+	task    *Task
+	values  chan interface{}
+	current interface{}
+
+	// Protects values from being closed while a value is being yielded
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewGenerator creates a new generator, the generator function is given a
+// yield function that blocks until the yielded value has been received.
+// Yield returns false once the generator has been stopped, at which point
+// the generator function should return as soon as it can.
+//
+// The generator function may also resolve or reject the underlying task as
+// usual, a rejection is reported by Err once all values have been received.
+//
+// For example:
+// 	g := task.NewGenerator(func(t *task.Internal, yield func(v interface{}) bool) {
+// 		for i := 0; i < 10; i++ {
+// 			if !yield(i) {
+// 				return
+// 			}
+// 		}
+// 	})
+// 	for v, err := range g.All() {
+// 	}
+func NewGenerator(fn func(t *Internal, yield func(v interface{}) bool)) *Generator {
+	return NewGeneratorWithBuffer(0, fn)
+}
+
+// NewGeneratorWithBuffer does the same as NewGenerator but yield will only
+// block once size values are waiting to be received.
+func NewGeneratorWithBuffer(size int, fn func(t *Internal, yield func(v interface{}) bool)) *Generator {
+	g := &Generator{values: make(chan interface{}, size)}
+	g.task = New(func(t *Internal) {
+		fn(t, func(v interface{}) bool {
+			return g.yield(t, v)
+		})
+	})
+
+	// The channel is closed once the task is done, even if the generator function panicked
+	go func() {
+		<-*g.task.Done
+		g.mu.Lock()
+		g.closed = true
+		close(g.values)
+		g.mu.Unlock()
+	}()
+
+	return g
+}
+
+// yield sends v to the consumer, unless the generator has been stopped or is done
+func (g *Generator) yield(t *Internal, v interface{}) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if g.closed || t.ShouldStop() {
+		return false
+	}
+	select {
+	case g.values <- v:
+		return true
+	case <-*t.Stopper:
+		return false
+	case <-*t.done:
+		return false
+	}
+}
+
+// Task returns the task that runs the generator function. It can be used with
+// all the usual await & stop helpers, stopping it is the same as Stop.
+func (g *Generator) Task() *Task {
+	return g.task
+}
+
+// C returns a channel that receives each yielded value, the channel is closed
+// once the generator function has returned.
+func (g *Generator) C() <-chan interface{} {
+	return g.values
+}
+
+// Wait will return true once the next value has been yielded, it will return
+// false once the generator function has returned & all values have been received.
+func (g *Generator) Wait() bool {
+	v, ok := <-g.values
+	if !ok {
+		return false
+	}
+	g.current = v
+	return true
+}
+
+// Value returns the value received by the last call to Wait.
+func (g *Generator) Value() interface{} {
+	return g.current
+}
+
+// Err waits for the generator function to return & then returns it's final
+// error, an ErrTaskStopped is returned if the generator was stopped.
+func (g *Generator) Err() error {
+	_, err := g.task.Result()
+	return err
+}
+
+// All returns an iterator that yields each value as it is yielded by the
+// generator function. If the generator finishes with an error it is yielded
+// last with a nil value. Breaking out of the loop early will stop the generator.
+func (g *Generator) All() iter.Seq2[interface{}, error] {
+	return func(yield func(interface{}, error) bool) {
+		for g.Wait() {
+			if !yield(g.Value(), nil) {
+				g.Stop()
+				return
+			}
+		}
+		if err := g.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// Stop will cooperatively stop the generator & wait for it to return.
+func (g *Generator) Stop() {
+	g.task.Stop()
+}
@@ -0,0 +1,102 @@
+package task_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/stretchr/testify/assert"
+)
+
+// drained returns true if fn returns within a second
+func drained(fn func()) bool {
+	done := make(chan struct{})
+	go func() {
+		fn()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(time.Second):
+		return false
+	}
+}
+
+func TestGeneratorYieldsValuesThenError(t *testing.T) {
+	g := task.NewGenerator(func(t *task.Internal, yield func(v interface{}) bool) {
+		for i := 0; i < 3; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+		t.Reject(errors.New("boom"))
+	})
+
+	values := []interface{}{}
+	var last error
+	for v, err := range g.All() {
+		if err != nil {
+			last = err
+			continue
+		}
+		values = append(values, v)
+	}
+
+	assert.Equal(t, []interface{}{0, 1, 2}, values)
+	assert.Error(t, last)
+	assert.Equal(t, task.StateRejected, g.Task().State())
+}
+
+func TestGeneratorStoppedBeforeStarting(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		g := task.NewGenerator(func(t *task.Internal, yield func(v interface{}) bool) {
+			yield(1)
+		})
+		g.Stop()
+		if !assert.True(t, drained(func() {
+			for g.Wait() {
+			}
+		}), "consumer hung") {
+			return
+		}
+		var stopped *task.ErrTaskStopped
+		assert.ErrorAs(t, g.Err(), &stopped)
+	}
+}
+
+func TestGeneratorStoppedByBreaking(t *testing.T) {
+	stopped := make(chan struct{})
+	g := task.NewGeneratorWithBuffer(2, func(t *task.Internal, yield func(v interface{}) bool) {
+		defer close(stopped)
+		for i := 0; ; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	})
+
+	for v := range g.All() {
+		if v == 3 {
+			break
+		}
+	}
+
+	assert.True(t, drained(func() { <-stopped }))
+	assert.Equal(t, task.StateStopped, g.Task().State())
+	assert.True(t, drained(func() {
+		for range g.C() {
+		}
+	}))
+}
+
+func TestGeneratorYieldAfterDone(t *testing.T) {
+	var yield func(v interface{}) bool
+	g := task.NewGenerator(func(t *task.Internal, y func(v interface{}) bool) {
+		yield = y
+	})
+	for g.Wait() {
+	}
+	assert.False(t, yield(1))
+}
@@ -0,0 +1,257 @@
+package task
+
+import (
+	"iter"
+	"math/rand"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/clock"
+)
+
+// Overrun decides what a Schedule does when a run is due
+// but the previous run has not yet finished.
+type Overrun int
+
+const (
+	// SkipOverrun skips any runs that are due while the previous run is still running.
+	SkipOverrun Overrun = iota
+
+	// QueueOverrun starts the runs that were due as soon as the previous run has finished.
+	QueueOverrun
+)
+
+// ScheduleOptions configures a Schedule.
+type ScheduleOptions struct {
+	// What to do when a run is due but the previous run
+	// has not yet finished, defaults to SkipOverrun.
+	Overrun Overrun
+
+	// A random delay of up to this duration is added to each run,
+	// useful to stop many schedules from running at exactly the same time.
+	Jitter time.Duration
+
+	// The schedule resolves once this many runs have finished,
+	// zero means there is no limit.
+	MaxRuns int
+
+	// The number of finished runs that are kept until they are received with
+	// C, Wait or All. Once full the oldest run is dropped so a schedule is never
+	// held up by a slow consumer. Defaults to 16.
+	Buffer int
+
+	// Used to decide when runs are due, defaults to clock.Default()
+	Clock clock.Clock
+}
+
+// Schedule executes a function repeatedly, create new instances with Every
+// or Cron. Runs never overlap, each run is a task of it's own & once it has
+// finished it can be received with C, Wait or All in order to monitor it.
+//
+// Stopping the schedule also stops the current run.
+type Schedule struct {
+	task    *Task
+	runs    chan *Task
+	current *Task
+}
+
+// After creates a task that executes fn once the given duration has passed.
+// The task settles with the result of fn, stopping it before then means fn
+// is never executed.
+//
+// Accepts `func()` or `func(t *task.Internal)`
+func After(d time.Duration, fn interface{}) *Task {
+	return New(func(t *Internal) {
+		timer := clock.Default().NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-timer.C():
+			t.follow(t.Spawn(fn))
+		case <-*t.Stopper:
+		}
+	})
+}
+
+// Every creates a schedule that executes fn every interval,
+// the first run starts after the first interval.
+//
+// Accepts `func()` or `func(t *task.Internal)`
+//
+// For example:
+// 	s := task.Every(time.Minute, pollAsync, task.ScheduleOptions{})
+// 	for _, err := range s.All() {
+// 		if err != nil {
+// 			log.Println("poll failed", err)
+// 		}
+// 	}
+func Every(interval time.Duration, fn interface{}, options ScheduleOptions) *Schedule {
+	return newSchedule(func(after time.Time) time.Time {
+		return after.Add(interval)
+	}, fn, options)
+}
+
+// Cron creates a schedule that executes fn at the times matched by the given
+// cron expression, see ParseCron for the supported syntax.
+//
+// Accepts `func()` or `func(t *task.Internal)`
+func Cron(expr string, fn interface{}, options ScheduleOptions) (*Schedule, error) {
+	c, err := ParseCron(expr)
+	if err != nil {
+		return nil, err
+	}
+	return newSchedule(c.Next, fn, options), nil
+}
+
+// newSchedule does the actual work for Every & Cron,
+// next returns the time of the run that follows after.
+func newSchedule(next func(after time.Time) time.Time, fn interface{}, options ScheduleOptions) *Schedule {
+	if options.Buffer <= 0 {
+		options.Buffer = 16
+	}
+	options.Clock = clock.Or(options.Clock)
+
+	s := &Schedule{runs: make(chan *Task, options.Buffer)}
+	s.task = New(func(t *Internal) {
+		var running *Task
+		var runningDone chan struct{}
+		started := 0
+		queued := 0
+
+		// More is false once no more runs are due
+		due := next(options.Clock.Now())
+		more := !due.IsZero()
+		if !more {
+			return
+		}
+		timer := options.Clock.NewTimer(s.delay(due, options))
+		defer timer.Stop()
+
+		start := func() {
+			running = NewWithContext(t.ctx, fn)
+			runningDone = *running.Done
+			started++
+		}
+
+		for {
+			select {
+			case <-*t.Stopper:
+				if running != nil {
+					running.Stop()
+					s.publish(running)
+				}
+				return
+
+			case <-timer.C():
+				switch {
+				case running == nil:
+					start()
+				case options.Overrun == QueueOverrun:
+					queued++
+				}
+				if options.MaxRuns > 0 && started+queued >= options.MaxRuns {
+					more = false
+				}
+				if more {
+					due = next(due)
+					more = !due.IsZero()
+				}
+				if more {
+					timer.Reset(s.delay(due, options))
+				}
+
+			case <-runningDone:
+				s.publish(running)
+				running = nil
+				runningDone = nil
+				if queued > 0 {
+					queued--
+					start()
+				} else if !more {
+					return
+				}
+			}
+		}
+	})
+
+	// The channel is closed once the task is done, even if the body panicked
+	go func() {
+		<-*s.task.Done
+		close(s.runs)
+	}()
+
+	return s
+}
+
+// delay returns how long to wait until due, plus some jitter
+func (s *Schedule) delay(due time.Time, options ScheduleOptions) time.Duration {
+	d := options.Clock.Until(due)
+	if options.Jitter > 0 {
+		d += time.Duration(rand.Int63n(int64(options.Jitter)))
+	}
+	return d
+}
+
+// publish makes a finished run available to C, dropping the oldest run if need be
+func (s *Schedule) publish(run *Task) {
+	for {
+		select {
+		case s.runs <- run:
+			return
+		default:
+		}
+		select {
+		case <-s.runs:
+		default:
+		}
+	}
+}
+
+// Task returns the task that runs the schedule. It can be used with
+// all the usual await & stop helpers, stopping it is the same as Stop.
+func (s *Schedule) Task() *Task {
+	return s.task
+}
+
+// Stop will cooperatively stop the schedule & the current run.
+func (s *Schedule) Stop() {
+	s.task.Stop()
+}
+
+// C returns a channel that receives each run once it has finished,
+// the channel is closed once the schedule is done.
+func (s *Schedule) C() <-chan *Task {
+	return s.runs
+}
+
+// Wait will return true once the next run has finished, it will return
+// false once the schedule is done & all finished runs have been received.
+func (s *Schedule) Wait() bool {
+	run, ok := <-s.runs
+	if !ok {
+		return false
+	}
+	s.current = run
+	return true
+}
+
+// Run returns the run received by the last call to Wait.
+func (s *Schedule) Run() *Task {
+	return s.current
+}
+
+// Result is an alias for the Result method of the run received by the last call to Wait.
+func (s *Schedule) Result() (interface{}, error) {
+	return s.current.Result()
+}
+
+// All returns an iterator that yields the result of each run as it finishes.
+// Breaking out of the loop early will stop the schedule.
+func (s *Schedule) All() iter.Seq2[interface{}, error] {
+	return func(yield func(interface{}, error) bool) {
+		for s.Wait() {
+			if !yield(s.Result()) {
+				s.Stop()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package task_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/clock/fake"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCronNext(t *testing.T) {
+	after := time.Date(2024, 1, 31, 10, 7, 30, 0, time.UTC)
+	for expr, expected := range map[string]string{
+		"*/15 * * * *":      "2024-01-31T10:15:00Z",
+		"0 9 * * MON-FRI":   "2024-02-01T09:00:00Z",
+		"0 0 29 2 *":        "2024-02-29T00:00:00Z",
+		"@monthly":          "2024-02-01T00:00:00Z",
+		"30 8 1,15 * *":     "2024-02-01T08:30:00Z",
+		"0 12 13 * 5":       "2024-02-02T12:00:00Z",
+		"0 0 * * 7":         "2024-02-04T00:00:00Z",
+		"5 10-12/2 * jan *": "2024-01-31T12:05:00Z",
+	} {
+		c, err := task.ParseCron(expr)
+		if assert.NoError(t, err, expr) {
+			assert.Equal(t, expected, c.Next(after).Format(time.RFC3339), expr)
+		}
+	}
+
+	c, err := task.ParseCron("0 0 30 2 *")
+	assert.NoError(t, err)
+	assert.True(t, c.Next(after).IsZero())
+}
+
+func TestCronInvalid(t *testing.T) {
+	for _, expr := range []string{"* * *", "60 * * * *", "*/0 * * * *", "x * * * *", "5-1 * * * *"} {
+		_, err := task.ParseCron(expr)
+		var invalid *task.ErrInvalidCron
+		assert.ErrorAs(t, err, &invalid, expr)
+	}
+}
+
+func TestEveryMaxRuns(t *testing.T) {
+	c := fake.New(time.Now())
+	runs := 0
+	s := task.Every(time.Minute, func(t *task.Internal) {
+		runs++
+		if runs%2 == 0 {
+			t.Reject(errors.New("even"))
+			return
+		}
+		t.Resolve(runs)
+	}, task.ScheduleOptions{MaxRuns: 4, Clock: c})
+
+	go func() {
+		for !s.Task().IsCompleted() {
+			c.Advance(time.Minute)
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	failures := 0
+	received := 0
+	for _, err := range s.All() {
+		received++
+		if err != nil {
+			failures++
+		}
+	}
+
+	assert.Equal(t, 4, received)
+	assert.Equal(t, 2, failures)
+	assert.Equal(t, task.StateCompleted, s.Task().State())
+}
+
+func TestEveryOverrun(t *testing.T) {
+	// A run is due while the first run is still running, once the first run
+	// finishes a queued run starts straight away where as a skipped run does not.
+	for _, overrun := range []task.Overrun{task.SkipOverrun, task.QueueOverrun} {
+		c := fake.New(time.Now())
+		started := make(chan struct{}, 2)
+		release := make(chan struct{})
+		s := task.Every(time.Minute, func() {
+			started <- struct{}{}
+			<-release
+		}, task.ScheduleOptions{Overrun: overrun, Clock: c})
+
+		c.BlockUntil(1)
+		c.Advance(time.Minute)
+		<-started
+		c.BlockUntil(1)
+		c.Advance(time.Minute)
+		c.BlockUntil(1)
+		release <- struct{}{}
+
+		select {
+		case <-started:
+			assert.Equal(t, task.QueueOverrun, overrun)
+		case <-time.After(50 * time.Millisecond):
+			assert.Equal(t, task.SkipOverrun, overrun)
+		}
+
+		close(release)
+		s.Stop()
+	}
+}
+
+func TestScheduleStoppedBeforeStarting(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		s := task.Every(time.Hour, func() {}, task.ScheduleOptions{})
+		s.Stop()
+		if !assert.True(t, drained(func() {
+			for s.Wait() {
+			}
+		}), "consumer hung") {
+			return
+		}
+	}
+}
+
+func TestAfter(t *testing.T) {
+	v, err := task.After(10*time.Millisecond, func(t *task.Internal) { t.Resolve("x") }).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, "x", v)
+
+	ran := false
+	a := task.After(time.Hour, func() { ran = true })
+	a.Stop()
+	assert.False(t, ran)
+	assert.Equal(t, task.StateStopped, a.State())
+}
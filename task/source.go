@@ -0,0 +1,92 @@
+package task
+
+import (
+	"sync"
+
+	"github.com/brad-jones/goerr/v2"
+)
+
+// Source is a task that is resolved, rejected or canceled from the outside
+// rather than by a function, much like C#'s TaskCompletionSource or a JS
+// deferred. Create new instances with NewSource.
+//
+// All methods are safe to call concurrently and many times over, only the
+// first call to Resolve, Reject or Cancel has any effect.
+type Source struct {
+	task    *Task
+	once    sync.Once
+	settler chan func(t *Internal)
+
+	// Held while checking for a stop & sending to settler, so that a stopped
+	// task can tell if it lost the race with Resolve or Reject
+	mu sync.Mutex
+}
+
+// NewSource creates a new pending Source.
+func NewSource() *Source {
+	s := &Source{settler: make(chan func(t *Internal), 1)}
+	s.task = New(func(t *Internal) {
+		select {
+		case settle := <-s.settler:
+			settle(t)
+		case <-*t.Stopper:
+			// Resolve or Reject may have settled the task just before it was stopped
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			select {
+			case settle := <-s.settler:
+				settle(t)
+			default:
+			}
+		}
+	})
+	return s
+}
+
+// Task returns the task that is controlled by this source. It can be used with
+// all the usual await & stop helpers, stopping it is the same as Cancel.
+func (s *Source) Task() *Task {
+	return s.task
+}
+
+// Resolve the task with the given value.
+// Returns true if this call was the one that settled the task.
+func (s *Source) Resolve(v interface{}) bool {
+	return s.settle(func(t *Internal) {
+		t.Resolver <- v
+	})
+}
+
+// Reject the task with the given error.
+// Returns true if this call was the one that settled the task.
+func (s *Source) Reject(err interface{}, messages ...string) bool {
+	e := goerr.Trace(1, err, messages...)
+	return s.settle(func(t *Internal) {
+		t.Rejector <- e
+	})
+}
+
+// Cancel stops the task without resolving or rejecting anything,
+// Result will return an ErrTaskStopped.
+// Returns true if this call was the one that settled the task.
+func (s *Source) Cancel() bool {
+	settled := false
+	s.once.Do(func() {
+		settled = !s.task.stopRequested()
+		s.task.signalStop()
+	})
+	return settled
+}
+
+func (s *Source) settle(fn func(t *Internal)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	settled := false
+	s.once.Do(func() {
+		if !s.task.stopRequested() {
+			settled = true
+			s.settler <- fn
+		}
+	})
+	return settled
+}
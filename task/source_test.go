@@ -0,0 +1,131 @@
+package task_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceResolve(t *testing.T) {
+	s := task.NewSource()
+	assert.True(t, s.Resolve(1))
+	assert.False(t, s.Resolve(2))
+	assert.False(t, s.Reject(errors.New("boom")))
+	assert.False(t, s.Cancel())
+
+	v, err := s.Task().Result()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, task.StateResolved, s.Task().State())
+}
+
+func TestSourceReject(t *testing.T) {
+	errBoom := errors.New("boom")
+	s := task.NewSource()
+	assert.True(t, s.Reject(errBoom))
+	assert.False(t, s.Resolve(1))
+
+	_, err := s.Task().Result()
+	assert.ErrorIs(t, err, errBoom)
+	assert.Equal(t, task.StateRejected, s.Task().State())
+}
+
+func TestSourceCancel(t *testing.T) {
+	s := task.NewSource()
+	assert.True(t, s.Cancel())
+	assert.False(t, s.Cancel())
+	assert.False(t, s.Resolve(1))
+
+	_, err := s.Task().Result()
+	assert.ErrorAs(t, err, new(*task.ErrTaskStopped))
+	assert.Equal(t, task.StateStopped, s.Task().State())
+}
+
+func TestSourceStop(t *testing.T) {
+	s := task.NewSource()
+	s.Task().Stop()
+	assert.False(t, s.Resolve(1))
+	assert.False(t, s.Cancel())
+	assert.Equal(t, task.StateStopped, s.Task().State())
+}
+
+func TestSourceConcurrent(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		s := task.NewSource()
+		wins := make(chan string, 30)
+		wg := sync.WaitGroup{}
+		for j := 0; j < 10; j++ {
+			wg.Add(3)
+			go func() {
+				defer wg.Done()
+				if s.Resolve(1) {
+					wins <- "resolve"
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				if s.Reject(errors.New("boom")) {
+					wins <- "reject"
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				if s.Cancel() {
+					wins <- "cancel"
+				}
+			}()
+		}
+		wg.Wait()
+		close(wins)
+
+		winners := []string{}
+		for w := range wins {
+			winners = append(winners, w)
+		}
+		if !assert.Len(t, winners, 1) {
+			return
+		}
+
+		<-*s.Task().Done
+		expected := map[string]task.State{
+			"resolve": task.StateResolved,
+			"reject":  task.StateRejected,
+			"cancel":  task.StateStopped,
+		}[winners[0]]
+		assert.Equal(t, expected, s.Task().State())
+	}
+}
+
+func TestSourceResolveThenStop(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		s := task.NewSource()
+		assert.True(t, s.Resolve(1))
+		s.Task().Stop()
+		if !assert.Equal(t, task.StateResolved, s.Task().State()) {
+			return
+		}
+	}
+}
+
+func TestSourceStopRacingResolve(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		s := task.NewSource()
+		resolved := make(chan bool)
+		go func() { resolved <- s.Resolve(1) }()
+		s.Task().Stop()
+
+		// Whichever came first decides the outcome
+		if <-resolved {
+			v, err := s.Task().Result()
+			if !assert.NoError(t, err) || !assert.Equal(t, 1, v) {
+				return
+			}
+			assert.Equal(t, task.StateResolved, s.Task().State())
+		} else {
+			assert.Equal(t, task.StateStopped, s.Task().State())
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package task
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/clock"
+)
+
+// State represents a stage in the lifecycle of a task.
+//
+// A task starts out pending, moves to running once it's function has been
+// invoked and then ends in exactly one of the final states.
+type State int
+
+const (
+	// StatePending tasks have been created but have not started running yet.
+	StatePending State = iota
+
+	// StateRunning tasks are currently executing.
+	StateRunning
+
+	// StateResolved tasks finished and resolved a value.
+	StateResolved
+
+	// StateRejected tasks finished and rejected an error.
+	StateRejected
+
+	// StateStopped tasks were told to stop and did not resolve a value.
+	StateStopped
+
+	// StatePanicked tasks panicked, the error will be an ErrTaskPanicked.
+	StatePanicked
+
+	// StateTimedOut tasks were stopped by ResultWithTimeout and did not resolve a value.
+	StateTimedOut
+
+	// StateCompleted tasks finished without resolving or rejecting anything.
+	StateCompleted
+)
+
+// numStates is used to size the array of timestamps
+const numStates = int(StateCompleted) + 1
+
+func (s State) String() string {
+	switch s {
+	case StatePending:
+		return "pending"
+	case StateRunning:
+		return "running"
+	case StateResolved:
+		return "resolved"
+	case StateRejected:
+		return "rejected"
+	case StateStopped:
+		return "stopped"
+	case StatePanicked:
+		return "panicked"
+	case StateTimedOut:
+		return "timed out"
+	case StateCompleted:
+		return "completed"
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// IsFinal returns true if the task can not move to any other state.
+func (s State) IsFinal() bool {
+	return s >= StateResolved
+}
+
+// State returns the current state of the task in a non blocking manner.
+func (t *Task) State() State {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.state
+}
+
+// EnteredAt returns the time at which the task entered the given state,
+// the second return value will be false if the task never entered the state.
+func (t *Task) EnteredAt(s State) (time.Time, bool) {
+	if s < 0 || int(s) >= numStates {
+		return time.Time{}, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ts := t.timestamps[s]
+	return ts, !ts.IsZero()
+}
+
+// transition moves the task to the given state, recording when that happened.
+// Callers must hold t.mu
+func (t *Task) transition(s State) {
+	t.state = s
+	t.timestamps[s] = clock.Default().Now()
+}
+
+// settle moves the task to it's final state. The final state is refined to
+// stopped or timed out if the task was told to stop and did not resolve a value.
+func (t *Task) settle(s State) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if (s == StateRejected || s == StateCompleted) && t.stopRequested() {
+		if t.timedOut {
+			s = StateTimedOut
+		} else {
+			s = StateStopped
+		}
+	}
+
+	if t.err == nil {
+		switch s {
+		case StateStopped:
+			t.err = &ErrTaskStopped{}
+		case StateTimedOut:
+			t.err = &ErrTaskTimedOut{}
+		}
+	}
+
+	t.transition(s)
+}
+
+// stopRequested is a non blocking check to see if the stopper has been closed.
+func (t *Task) stopRequested() bool {
+	select {
+	case <-*t.Stopper:
+		return true
+	default:
+		return false
+	}
+}
+
+// ErrTaskStopped is returned by Result when a task was told to stop
+// and it returned without resolving or rejecting anything.
+type ErrTaskStopped struct {
+}
+
+func (e *ErrTaskStopped) Error() string {
+	return "task: stopped before a result was resolved"
+}
+
+// ErrTaskTimedOut is returned by Result when a task was stopped by
+// ResultWithTimeout and it returned without resolving or rejecting anything.
+type ErrTaskTimedOut struct {
+}
+
+func (e *ErrTaskTimedOut) Error() string {
+	return "task: timed out before a result was resolved"
+}
+
+// ErrTaskPanicked is returned by Result when a task panicked.
+type ErrTaskPanicked struct {
+	// The recovered value as an error
+	Err error
+
+	// The stack of the goroutine at the time it panicked
+	Stack []byte
+}
+
+func (e *ErrTaskPanicked) Error() string {
+	return "task: panicked: " + e.Err.Error()
+}
+
+func (e *ErrTaskPanicked) Unwrap() error {
+	return e.Err
+}
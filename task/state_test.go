@@ -0,0 +1,162 @@
+package task_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/clock"
+	"github.com/brad-jones/goasync/v2/clock/fake"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/stretchr/testify/assert"
+)
+
+// untilStopped returns a running task that runs fn once it has been told to stop
+func untilStopped(fn func(t *task.Internal)) *task.Task {
+	running := make(chan struct{})
+	t := task.New(func(t *task.Internal) {
+		close(running)
+		<-*t.Stopper
+		fn(t)
+	})
+	<-running
+	return t
+}
+
+func TestStateResolved(t *testing.T) {
+	tsk := task.New(func(t *task.Internal) { t.Resolve(1) })
+	v, err := tsk.Result()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, task.StateResolved, tsk.State())
+
+	// Resolving a value takes precedence over being told to stop
+	tsk = untilStopped(func(t *task.Internal) { t.Resolve(2) })
+	tsk.Stop()
+	v, err = tsk.Result()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, v)
+	assert.Equal(t, task.StateResolved, tsk.State())
+}
+
+func TestStateRejected(t *testing.T) {
+	tsk := task.New(func(t *task.Internal) { t.Reject(errors.New("boom")) })
+	assert.EqualError(t, tsk.Wait(), "boom")
+	assert.Equal(t, task.StateRejected, tsk.State())
+}
+
+func TestStateStopped(t *testing.T) {
+	var stopped *task.ErrTaskStopped
+
+	tsk := untilStopped(func(t *task.Internal) {})
+	tsk.Stop()
+	v, err := tsk.Result()
+	assert.Nil(t, v)
+	assert.ErrorAs(t, err, &stopped)
+	assert.Equal(t, task.StateStopped, tsk.State())
+
+	// A task that rejects once told to stop keeps it's own error
+	tsk = untilStopped(func(t *task.Internal) { t.Reject(errors.New("interrupted")) })
+	tsk.Stop()
+	assert.EqualError(t, tsk.Wait(), "interrupted")
+	assert.Equal(t, task.StateStopped, tsk.State())
+
+	// A pending task stopped before it started never runs
+	ran := false
+	tsk = task.NewPending(func() { ran = true })
+	go tsk.Stop()
+	assert.Eventually(t, func() bool {
+		select {
+		case <-*tsk.Stopper:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, time.Millisecond)
+	tsk.Run()
+	assert.False(t, ran)
+	assert.ErrorAs(t, tsk.Wait(), &stopped)
+	assert.Equal(t, task.StateStopped, tsk.State())
+}
+
+func TestNewRunsEvenIfStoppedStraightAway(t *testing.T) {
+	// Only pending tasks are skipped, a task created with New always gets
+	// the chance to clean up once it notices it has been told to stop.
+	for i := 0; i < 1000; i++ {
+		ran := false
+		tsk := task.New(func(t *task.Internal) {
+			ran = true
+		})
+		tsk.Stop()
+		if !assert.True(t, ran) {
+			return
+		}
+	}
+}
+
+func TestStatePanicked(t *testing.T) {
+	tsk := task.New(func() { panic("boom") })
+	var panicked *task.ErrTaskPanicked
+	if assert.ErrorAs(t, tsk.Wait(), &panicked) {
+		assert.EqualError(t, panicked.Err, "boom")
+		assert.NotEmpty(t, panicked.Stack)
+	}
+	assert.Equal(t, task.StatePanicked, tsk.State())
+}
+
+func TestStateTimedOut(t *testing.T) {
+	var timedOut *task.ErrTaskTimedOut
+
+	tsk := untilStopped(func(t *task.Internal) {})
+	_, err := tsk.ResultWithTimeout(time.Millisecond, time.Second)
+	assert.ErrorAs(t, err, &timedOut)
+	assert.Equal(t, task.StateTimedOut, tsk.State())
+
+	_, err = tsk.Result()
+	assert.ErrorAs(t, err, &timedOut)
+}
+
+func TestStateCompleted(t *testing.T) {
+	tsk := task.New(func() {})
+	v, err := tsk.Result()
+	assert.Nil(t, v)
+	assert.NoError(t, err)
+	assert.Equal(t, task.StateCompleted, tsk.State())
+}
+
+func TestEnteredAt(t *testing.T) {
+	c := fake.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer clock.SetDefault(clock.SetDefault(c))
+
+	release := make(chan struct{})
+	tsk := task.NewPending(func(t *task.Internal) {
+		<-release
+		t.Resolve(1)
+	})
+	created := c.Now()
+	assert.Equal(t, task.StatePending, tsk.State())
+
+	c.Advance(time.Second)
+	tsk.Start()
+	assert.Eventually(t, func() bool { return tsk.State() == task.StateRunning }, time.Second, time.Millisecond)
+
+	c.Advance(time.Second)
+	close(release)
+	assert.NoError(t, tsk.Wait())
+
+	for s, expected := range map[task.State]time.Time{
+		task.StatePending:  created,
+		task.StateRunning:  created.Add(time.Second),
+		task.StateResolved: created.Add(2 * time.Second),
+	} {
+		at, ok := tsk.EnteredAt(s)
+		assert.True(t, ok, s.String())
+		assert.Equal(t, expected, at, s.String())
+	}
+
+	_, ok := tsk.EnteredAt(task.StateRejected)
+	assert.False(t, ok)
+	_, ok = tsk.EnteredAt(task.State(100))
+	assert.False(t, ok)
+	assert.Equal(t, "State(100)", task.State(100).String())
+}
@@ -3,8 +3,11 @@ package task
 
 import (
 	"context"
+	"runtime/debug"
+	"sync"
 	"time"
 
+	"github.com/brad-jones/goasync/v2/clock"
 	"github.com/brad-jones/goerr/v2"
 )
 
@@ -30,20 +33,38 @@ type Task struct {
 	// they don't necessarily have to resolve something.
 	Done *chan struct{}
 
-	// This will be set to true when the task has completed
-	doneValue bool
+	// The current lifecycle state of the task
+	state State
+
+	// When the task entered each state
+	timestamps [numStates]time.Time
+
+	// Set by ResultWithTimeout before it stops the task
+	timedOut bool
+
+	// Set for tasks created with NewPending, which
+	// never run if they are stopped before they start
+	pending bool
 
 	// We keep a copy of the value for use with Result()
 	value interface{}
 
 	// We keep a copy of the error for use with Result()
 	err error
+
+	// Child tasks that have been spawned or adopted by this task
+	children []*Task
+
+	// The function that executes the task, nil once the task has started
+	body func()
+
+	// Protects children, state, timestamps, timedOut & body
+	mu sync.Mutex
 }
 
 // Stop the task cooperatively, this will block until the task has returned.
 func (t *Task) Stop() {
-	defer func() { recover() }()
-	close(*t.Stopper)
+	t.signalStop()
 	<-*t.Done
 }
 
@@ -51,13 +72,15 @@ func (t *Task) Stop() {
 // a timeout is reached. Use this to ensure your application does not
 // hang indefinitely.
 func (t *Task) StopWithTimeout(timeout time.Duration) error {
-	defer func() { recover() }()
-	close(*t.Stopper)
+	t.signalStop()
+
+	timer := clock.Default().NewTimer(timeout)
+	defer timer.Stop()
 
 	select {
 	case <-*t.Done:
 		return nil
-	case <-time.After(timeout):
+	case <-timer.C():
 		return goerr.Wrap(&ErrStoppingTaskTimeout{})
 	}
 }
@@ -86,15 +109,40 @@ func (t *Task) MustResult() interface{} {
 	return v
 }
 
+// ResultCtx does the same as Result() but will stop waiting and return the
+// context's error if the context is done before the task is complete.
+// The task itself is not told to stop, see NewWithContext for that.
+func (t *Task) ResultCtx(ctx context.Context) (interface{}, error) {
+	select {
+	case <-*t.Done:
+		return t.value, t.err
+	case <-ctx.Done():
+		return nil, goerr.Wrap(ctx.Err(), "context was done before a result was returned")
+	}
+}
+
+// MustResultCtx does the same as ResultCtx() but panics if an error was encountered.
+func (t *Task) MustResultCtx(ctx context.Context) interface{} {
+	v, e := t.ResultCtx(ctx)
+	goerr.Check(e)
+	return v
+}
+
 // ResultWithTimeout takes 2 duration values, the first is the amount of time
 // we will wait for the task to complete, if that time passes we will then call
 // `StopWithTimeout` which will wait for the second duration for the given task
 // to cooperatively stop.
 func (t *Task) ResultWithTimeout(runtime, stoptime time.Duration) (interface{}, error) {
+	timer := clock.Default().NewTimer(runtime)
+	defer timer.Stop()
+
 	select {
 	case <-*t.Done:
 		return t.value, t.err
-	case <-time.After(runtime):
+	case <-timer.C():
+		t.mu.Lock()
+		t.timedOut = true
+		t.mu.Unlock()
 		if err := t.StopWithTimeout(stoptime); err != nil {
 			return nil, goerr.Wrap(err, "result took too long to be returned and failed to stop in a timely manner")
 		}
@@ -111,21 +159,25 @@ func (t *Task) MustResultWithTimeout(runtime, stoptime time.Duration) interface{
 
 // Then registers a callback to be called when this Task completes.
 // Accepts `func()`, `func(t *Internal)` or `func(result interface{}, t *Internal)`
+//
+// If this task rejects then the callback is not called and the returned task
+// rejects with the same error, use Catch to handle errors.
 func (t *Task) Then(fn interface{}) *Task {
-	t2 := New(func(t2 *Internal) {
-		switch v := fn.(type) {
+	return t.derive(func(t2 *Internal) {
+		v, err := t.Result()
+		if err != nil {
+			t2.Reject(err)
+			return
+		}
+		switch fn := fn.(type) {
 		case func():
-			t.MustWait()
-			v()
+			fn()
 		case func(t *Internal):
-			t.MustWait()
-			v(t2)
+			fn(t2)
 		case func(result interface{}, t *Internal):
-			v(t.MustResult(), t2)
+			fn(v, t2)
 		}
 	})
-	t2.Stopper = t.Stopper
-	return t2
 }
 
 // Wait will block until the task is complete, if the task rejected an error it will be returned
@@ -142,9 +194,92 @@ func (t *Task) MustWait() {
 	goerr.Check(t.Wait())
 }
 
+// WaitCtx does the same as Wait but will stop waiting and return the
+// context's error if the context is done before the task is complete.
+// The task itself is not told to stop, see NewWithContext for that.
+func (t *Task) WaitCtx(ctx context.Context) error {
+	_, err := t.ResultCtx(ctx)
+	if err != nil {
+		return goerr.Wrap(err)
+	}
+	return nil
+}
+
+// MustWaitCtx does the same as WaitCtx but panics if an error was encountered
+func (t *Task) MustWaitCtx(ctx context.Context) {
+	goerr.Check(t.WaitCtx(ctx))
+}
+
 // IsCompleted indicates if the task has finished or not in a non-blocking manner
 func (t *Task) IsCompleted() bool {
-	return t.doneValue
+	return t.State().IsFinal()
+}
+
+// Start executes a pending task asynchronously,
+// it does nothing if the task has already started.
+func (t *Task) Start() {
+	t.mu.Lock()
+	pending := t.body != nil
+	t.mu.Unlock()
+	if pending {
+		go t.run()
+	}
+}
+
+// Run executes a pending task on the calling goroutine & blocks until it is
+// done, if the task has already started this simply waits for it to be done.
+func (t *Task) Run() {
+	t.run()
+	<-*t.Done
+}
+
+// run executes the body of the task if it has not already been executed.
+func (t *Task) run() {
+	t.mu.Lock()
+	body := t.body
+	t.body = nil
+	t.mu.Unlock()
+	if body != nil {
+		body()
+	}
+}
+
+// signalStop closes the stopper channel without waiting for the task to
+// return, it is safe to call many times over.
+func (t *Task) signalStop() {
+	defer func() { recover() }()
+	close(*t.Stopper)
+}
+
+// adopt registers child with this task, the child will be told to stop
+// when the given stopper is closed.
+func (t *Task) adopt(child *Task, stopper *chan struct{}) {
+	t.mu.Lock()
+	t.children = append(t.children, child)
+	t.mu.Unlock()
+
+	go func() {
+		select {
+		case <-*child.Done:
+		case <-*stopper:
+			child.signalStop()
+		}
+	}()
+}
+
+// awaitChildren blocks until every child task is done,
+// including any children that are adopted while we wait.
+func (t *Task) awaitChildren() {
+	for i := 0; ; i++ {
+		t.mu.Lock()
+		if i >= len(t.children) {
+			t.mu.Unlock()
+			return
+		}
+		child := t.children[i]
+		t.mu.Unlock()
+		<-*child.Done
+	}
 }
 
 // Internal is used by the task implementor.
@@ -167,6 +302,12 @@ type Internal struct {
 	// Used internally to track when the task has actually finished
 	// regardless of what has or hasn't been resolved/rejected.
 	done *chan struct{}
+
+	// The task that is being implemented
+	task *Task
+
+	// The context the task was created with
+	ctx context.Context
 }
 
 // Resolve is a simple function that sends the provided value to the resolver channel.
@@ -179,6 +320,17 @@ func (i *Internal) Reject(err interface{}, messages ...string) {
 	i.Rejector <- goerr.Trace(1, err, messages...)
 }
 
+// Settle will reject err if it is not nil, otherwise it resolves v.
+// This is useful for wrapping functions that return a value and an error,
+// eg: `t.Settle(foo())`
+func (i *Internal) Settle(v interface{}, err error) {
+	if err != nil {
+		i.Rejector <- goerr.Trace(1, err)
+		return
+	}
+	i.Resolver <- v
+}
+
 // ShouldStop is a non blocking method that informs your task if it should stop.
 func (i *Internal) ShouldStop() bool {
 	select {
@@ -189,10 +341,31 @@ func (i *Internal) ShouldStop() bool {
 	}
 }
 
+// Spawn creates a new child task that is bound to the lifetime of this task.
+// Accepts `func()` or `func(t *Internal)`
+//
+// The child will be told to stop when this task is told to stop and this task
+// will not be considered done until all of it's children are done. This means
+// no child can outlive the scope of it's parent.
+func (i *Internal) Spawn(fn interface{}) *Task {
+	return i.Adopt(NewWithContext(i.ctx, fn))
+}
+
+// Adopt does the same as Spawn but for an existing task. This is useful when
+// calling other functions that return tasks, eg: `t.Adopt(fooAsync())`
+func (i *Internal) Adopt(child *Task) *Task {
+	i.task.adopt(child, i.Stopper)
+	return child
+}
+
 // CancelableCtx returns a context object that will be canceled if this task is
 // told to stop, this is useful for integrating with more traditional go code.
+//
+// The context is derived from the context given to NewWithContext so any
+// values and deadlines are inherited, for tasks created with New it is
+// derived from context.Background().
 func (i *Internal) CancelableCtx() context.Context {
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(i.ctx)
 	go func() {
 		select {
 		case <-*i.done:
@@ -206,9 +379,44 @@ func (i *Internal) CancelableCtx() context.Context {
 // New creates new instances of Task.
 // Accepts `func()` or `func(t *Internal)`
 func New(fn interface{}) *Task {
+	return NewWithContext(context.Background(), fn)
+}
+
+// NewPending creates new instances of Task that do not execute until Start
+// or Run is called. This allows the goroutine that executes the task to be
+// chosen by the caller, for example a worker in a pool.
+//
+// Stopping a pending task will block until it has been started, at which
+// point it will stop immediately without executing the given function.
+// Accepts `func()` or `func(t *Internal)`
+func NewPending(fn interface{}) *Task {
+	stopper := make(chan struct{}, 1)
+	t := newPendingTask(context.Background(), &stopper, fn)
+	t.pending = true
+	return t
+}
+
+// NewWithContext creates new instances of Task that are bound to the given
+// context. The task will be told to stop when the context is canceled and the
+// context returned by Internal.CancelableCtx will be derived from it.
+// Accepts `func()` or `func(t *Internal)`
+func NewWithContext(ctx context.Context, fn interface{}) *Task {
+	stopper := make(chan struct{}, 1)
+	return newTask(ctx, &stopper, fn)
+}
+
+// newTask does the actual work of New, tasks that are derived from another
+// task (eg: Then) share the stopper of the task they are derived from.
+func newTask(ctx context.Context, stopper *chan struct{}, fn interface{}) *Task {
+	t := newPendingTask(ctx, stopper, fn)
+	go t.run()
+	return t
+}
+
+// newPendingTask creates a task that will not execute until it is run.
+func newPendingTask(ctx context.Context, stopper *chan struct{}, fn interface{}) *Task {
 	// Spin up some channels
 	done := make(chan struct{}, 1)
-	stopper := make(chan struct{}, 1)
 	tResolver := make(chan interface{}, 1)
 	tRejector := make(chan error, 1)
 	tiResolver := make(chan interface{}, 1)
@@ -218,24 +426,56 @@ func New(fn interface{}) *Task {
 	t := &Task{
 		Resolver: tResolver,
 		Rejector: tRejector,
-		Stopper:  &stopper,
+		Stopper:  stopper,
 		Done:     &done,
 	}
 
-	// Execute the task asynchronously
-	go func() {
+	t.transition(StatePending)
+	track(t)
+
+	// Stop the task when the parent context is canceled
+	if ctx.Done() != nil {
+		go func() {
+			select {
+			case <-done:
+			case <-ctx.Done():
+				t.signalStop()
+			}
+		}()
+	}
+
+	// Executes the task, this is called at most once by run
+	t.body = func() {
 		// Regardless of what the function does we know that it is done
+		final := StateCompleted
 		defer func() {
+			// The task may have noticed the canceled context before we did
+			if ctx.Err() != nil {
+				t.signalStop()
+			}
+			t.settle(final)
 			close(done)
-			t.doneValue = true
 		}()
 
+		// But not before all of it's children are also done
+		defer t.awaitChildren()
+
 		// Catch any panics and reject them
 		defer goerr.Handle(func(e error) {
-			t.err = goerr.Trace(3, e)
+			final = StatePanicked
+			t.err = goerr.Trace(3, &ErrTaskPanicked{Err: e, Stack: debug.Stack()})
 			tRejector <- t.err
 		})
 
+		t.mu.Lock()
+		t.transition(StateRunning)
+		t.mu.Unlock()
+
+		// Pending tasks that were told to stop before they started never run
+		if t.pending && t.stopRequested() {
+			return
+		}
+
 		// Execute the task
 		switch v := fn.(type) {
 		case func():
@@ -246,6 +486,8 @@ func New(fn interface{}) *Task {
 				Rejector: tiRejector,
 				Stopper:  t.Stopper,
 				done:     &done,
+				task:     t,
+				ctx:      ctx,
 			})
 		}
 
@@ -254,14 +496,16 @@ func New(fn interface{}) *Task {
 		// done could be enough.
 		select {
 		case v := <-tiResolver:
+			final = StateResolved
 			t.value = v
 			tResolver <- t.value
 		case e := <-tiRejector:
+			final = StateRejected
 			t.err = e
 			tRejector <- t.err
 		default:
 		}
-	}()
+	}
 
 	// Return the task object
 	return t
@@ -271,28 +515,36 @@ func New(fn interface{}) *Task {
 func Resolved(v interface{}) *Task {
 	done := make(chan struct{}, 1)
 	close(done)
+	stopper := make(chan struct{}, 1)
 	resolver := make(chan interface{}, 1)
 	resolver <- v
 	rejector := make(chan error, 1)
-	return &Task{
+	t := &Task{
 		Resolver: resolver,
 		Rejector: rejector,
-		Stopper:  &done,
+		Stopper:  &stopper,
 		Done:     &done,
+		value:    v,
 	}
+	t.transition(StateResolved)
+	return t
 }
 
 // Rejected returns a pre-rejected task
 func Rejected(e error) *Task {
 	done := make(chan struct{}, 1)
 	close(done)
+	stopper := make(chan struct{}, 1)
 	resolver := make(chan interface{}, 1)
 	rejector := make(chan error, 1)
 	rejector <- e
-	return &Task{
+	t := &Task{
 		Resolver: resolver,
 		Rejector: rejector,
-		Stopper:  &done,
+		Stopper:  &stopper,
 		Done:     &done,
+		err:      e,
 	}
+	t.transition(StateRejected)
+	return t
 }
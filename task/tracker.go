@@ -0,0 +1,75 @@
+package task
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Tracker records every task created while it is open, create new instances
+// with NewTracker. It is intended for tests that check for leaked tasks, see
+// the asynctest package.
+//
+// Trackers see tasks created by any goroutine, so tasks created by tests
+// that run in parallel will be recorded by each other's trackers.
+type Tracker struct {
+	mu    sync.Mutex
+	tasks []*Task
+}
+
+// trackers is only locked when at least one tracker is open
+var trackers struct {
+	sync.RWMutex
+	open  int32
+	items map[*Tracker]struct{}
+}
+
+// NewTracker opens a new tracker, it must be closed with Close.
+func NewTracker() *Tracker {
+	tr := &Tracker{}
+	trackers.Lock()
+	defer trackers.Unlock()
+	if trackers.items == nil {
+		trackers.items = map[*Tracker]struct{}{}
+	}
+	trackers.items[tr] = struct{}{}
+	atomic.AddInt32(&trackers.open, 1)
+	return tr
+}
+
+// Live returns the recorded tasks that are not yet done,
+// including pending tasks that have not been started.
+func (tr *Tracker) Live() []*Task {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	live := []*Task{}
+	for _, t := range tr.tasks {
+		if !t.IsCompleted() {
+			live = append(live, t)
+		}
+	}
+	return live
+}
+
+// Close stops recording new tasks.
+func (tr *Tracker) Close() {
+	trackers.Lock()
+	defer trackers.Unlock()
+	if _, ok := trackers.items[tr]; ok {
+		delete(trackers.items, tr)
+		atomic.AddInt32(&trackers.open, -1)
+	}
+}
+
+// track records t with every open tracker
+func track(t *Task) {
+	if atomic.LoadInt32(&trackers.open) == 0 {
+		return
+	}
+	trackers.RLock()
+	defer trackers.RUnlock()
+	for tr := range trackers.items {
+		tr.mu.Lock()
+		tr.tasks = append(tr.tasks, t)
+		tr.mu.Unlock()
+	}
+}
@@ -0,0 +1,29 @@
+package task_test
+
+import (
+	"testing"
+
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracker(t *testing.T) {
+	before := task.NewSource()
+	defer before.Cancel()
+
+	tr := task.NewTracker()
+	live := task.NewSource()
+	done := task.New(func() {})
+	pending := task.NewPending(func() {})
+	done.Wait()
+
+	assert.ElementsMatch(t, []*task.Task{live.Task(), pending}, tr.Live())
+
+	tr.Close()
+	after := task.NewSource()
+	defer after.Cancel()
+	live.Cancel()
+	live.Task().Wait()
+	pending.Run()
+	assert.Empty(t, tr.Live())
+}
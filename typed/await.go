@@ -0,0 +1,88 @@
+package typed
+
+import (
+	"github.com/brad-jones/goasync/v2/await"
+	"github.com/brad-jones/goerr/v2"
+)
+
+// All is the typed equivalent of await.All
+func All[T any](awaitables ...*Task[T]) ([]T, error) {
+	values, err := await.All(untyped(awaitables)...)
+	if err != nil {
+		return nil, err
+	}
+	return castAll[T](values)
+}
+
+// MustAll does the same thing as All but panics if an error is encountered
+func MustAll[T any](awaitables ...*Task[T]) []T {
+	v, e := All(awaitables...)
+	goerr.Check(e)
+	return v
+}
+
+// AllAsync does the same thing as All but does so asynchronously
+func AllAsync[T any](awaitables ...*Task[T]) *Task[[]T] {
+	return New(func(t *Internal[[]T]) {
+		t.Settle(All(awaitables...))
+	})
+}
+
+// AllOrError is the typed equivalent of await.AllOrError
+func AllOrError[T any](awaitables ...*Task[T]) ([]T, error) {
+	values, err := await.AllOrError(untyped(awaitables)...)
+	if err != nil {
+		return nil, err
+	}
+	return castAll[T](values)
+}
+
+// MustAllOrError does the same thing as AllOrError but panics if an error is encountered
+func MustAllOrError[T any](awaitables ...*Task[T]) []T {
+	v, e := AllOrError(awaitables...)
+	goerr.Check(e)
+	return v
+}
+
+// AllOrErrorAsync does the same thing as AllOrError but does so asynchronously
+func AllOrErrorAsync[T any](awaitables ...*Task[T]) *Task[[]T] {
+	return New(func(t *Internal[[]T]) {
+		t.Settle(AllOrError(awaitables...))
+	})
+}
+
+// Any is the typed equivalent of await.Any
+func Any[T any](awaitables ...*Task[T]) (T, error) {
+	v, err := await.Any(untyped(awaitables)...)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return cast[T](v)
+}
+
+// MustAny does the same thing as Any but panics if an error is encountered
+func MustAny[T any](awaitables ...*Task[T]) T {
+	v, e := Any(awaitables...)
+	goerr.Check(e)
+	return v
+}
+
+// AnyAsync does the same thing as Any but does so asynchronously
+func AnyAsync[T any](awaitables ...*Task[T]) *Task[T] {
+	return New(func(t *Internal[T]) {
+		t.Settle(Any(awaitables...))
+	})
+}
+
+func castAll[T any](values []interface{}) ([]T, error) {
+	casted := make([]T, 0, len(values))
+	for _, value := range values {
+		v, err := cast[T](value)
+		if err != nil {
+			return nil, err
+		}
+		casted = append(casted, v)
+	}
+	return casted, nil
+}
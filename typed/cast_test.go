@@ -0,0 +1,42 @@
+package typed
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCast(t *testing.T) {
+	v, err := cast[int](1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+
+	// A task that did not resolve anything gives the zero value
+	s, err := cast[string](nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "", s)
+
+	// Values are asserted to interfaces as well as concrete types
+	r, err := cast[io.Reader](strings.NewReader("x"))
+	assert.NoError(t, err)
+	assert.NotNil(t, r)
+}
+
+func TestCastUnexpectedType(t *testing.T) {
+	v, err := cast[int]("one")
+	assert.Equal(t, 0, v)
+
+	var unexpected *ErrUnexpectedType
+	if assert.True(t, errors.As(err, &unexpected)) {
+		assert.Equal(t, reflect.TypeOf(0), unexpected.Expected)
+		assert.Equal(t, "one", unexpected.Value)
+	}
+	assert.EqualError(t, err, "typed: expected a value of type int but got string")
+
+	_, err = cast[io.Reader](1)
+	assert.EqualError(t, err, "typed: expected a value of type io.Reader but got int")
+}
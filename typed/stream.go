@@ -0,0 +1,63 @@
+package typed
+
+import (
+	"iter"
+
+	"github.com/brad-jones/goasync/v2/await"
+)
+
+// Stream is the typed equivalent of await.Stream
+//
+// For example:
+// 	s := typed.Stream(foo(), bar())
+// 	for s.Wait() {
+// 		r, err := s.Result()
+// 	}
+func Stream[T any](awaitables ...*Task[T]) *StreamInstance[T] {
+	return &StreamInstance[T]{inner: await.Stream(untyped(awaitables)...)}
+}
+
+// StreamInstance is the object that is returned by Stream
+type StreamInstance[T any] struct {
+	inner *await.StreamInstance
+}
+
+// Wait will return true once a task has finished & then remove that task from
+// the list of tasks to wait for. It will return false when there are no more
+// tasks to wait for.
+func (s *StreamInstance[T]) Wait() bool {
+	return s.inner.Wait()
+}
+
+// All returns an iterator that yields the result of each task as it finishes.
+// Breaking out of the loop early will stop all remaining tasks.
+func (s *StreamInstance[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for s.Wait() {
+			if !yield(s.Result()) {
+				s.Stop()
+				return
+			}
+		}
+	}
+}
+
+// Stop will cooperatively stop all remaining tasks.
+func (s *StreamInstance[T]) Stop() {
+	s.inner.Stop()
+}
+
+// Result is an alias for the completed task's Result method.
+func (s *StreamInstance[T]) Result() (T, error) {
+	return s.Task().Result()
+}
+
+// MustResult is an alias for the completed task's MustResult method.
+func (s *StreamInstance[T]) MustResult() T {
+	return s.Task().MustResult()
+}
+
+// Task return the completed task
+func (s *StreamInstance[T]) Task() *Task[T] {
+	return From[T](s.inner.Task())
+}
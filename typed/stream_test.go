@@ -0,0 +1,41 @@
+package typed_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/brad-jones/goasync/v2/typed"
+	"github.com/stretchr/testify/assert"
+)
+
+// blocked returns a task that only returns once it is told to stop
+func blocked() *typed.Task[int] {
+	return typed.New(func(t *typed.Internal[int]) {
+		<-*t.Stopper
+	})
+}
+
+func TestStreamAll(t *testing.T) {
+	sum := 0
+	errs := 0
+	for v, err := range typed.Stream(typed.Resolved(1), typed.Rejected[int](errors.New("boom")), typed.Resolved(2)).All() {
+		if err != nil {
+			errs++
+			continue
+		}
+		sum += v
+	}
+	assert.Equal(t, 3, sum)
+	assert.Equal(t, 1, errs)
+}
+
+func TestStreamAllBreak(t *testing.T) {
+	a, b := blocked(), blocked()
+	for v := range typed.Stream(typed.Resolved(1), a, b).All() {
+		assert.Equal(t, 1, v)
+		break
+	}
+	assert.Equal(t, task.StateStopped, a.State())
+	assert.Equal(t, task.StateStopped, b.State())
+}
@@ -0,0 +1,155 @@
+// Package typed is a generic, type-safe wrapper around task.Task.
+//
+// A typed.Task[T] embeds a regular *task.Task so it can still be passed to
+// anything that accepts the untyped API (stop.All, await.Any, etc) by simply
+// referencing the embedded Task field. This allows code to be migrated
+// gradually without losing compile-time checking of resolved values.
+package typed
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/brad-jones/goerr/v2"
+)
+
+// Task represents an asynchronous operation that resolves a value of type T,
+// create new instances with New or wrap an existing task with From.
+type Task[T any] struct {
+	*task.Task
+}
+
+// Result waits for the task to complete and then returns any resolved
+// (or rejected) values. If the resolved value is not of type T then an
+// ErrUnexpectedType will be returned.
+func (t *Task[T]) Result() (T, error) {
+	v, err := t.Task.Result()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return cast[T](v)
+}
+
+// MustResult does the same as Result() but panics if an error was rejected.
+func (t *Task[T]) MustResult() T {
+	v, e := t.Result()
+	goerr.Check(e)
+	return v
+}
+
+// ResultWithTimeout is the typed equivalent of task.Task.ResultWithTimeout.
+func (t *Task[T]) ResultWithTimeout(runtime, stoptime time.Duration) (T, error) {
+	v, err := t.Task.ResultWithTimeout(runtime, stoptime)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return cast[T](v)
+}
+
+// MustResultWithTimeout does the same as ResultWithTimeout() but panics if an error was encountered.
+func (t *Task[T]) MustResultWithTimeout(runtime, stoptime time.Duration) T {
+	v, e := t.ResultWithTimeout(runtime, stoptime)
+	goerr.Check(e)
+	return v
+}
+
+// Then registers a callback to be called when this Task resolves.
+// The new task resolves a value of the same type, use the package level
+// Then function if you need to resolve a different type.
+func (t *Task[T]) Then(fn func(result T, t *Internal[T])) *Task[T] {
+	return Then(t, fn)
+}
+
+// Internal is used by the task implementor.
+type Internal[T any] struct {
+	*task.Internal
+}
+
+// Resolve sends the provided value to the resolver channel.
+func (i *Internal[T]) Resolve(v T) {
+	i.Internal.Resolve(v)
+}
+
+// Settle will reject err if it is not nil, otherwise it resolves v.
+func (i *Internal[T]) Settle(v T, err error) {
+	i.Internal.Settle(v, err)
+}
+
+// New creates new instances of Task.
+func New[T any](fn func(t *Internal[T])) *Task[T] {
+	return From[T](task.New(func(t *task.Internal) {
+		fn(&Internal[T]{Internal: t})
+	}))
+}
+
+// From wraps an existing untyped task. The resolved value is only checked
+// against T once a result is requested.
+func From[T any](t *task.Task) *Task[T] {
+	return &Task[T]{Task: t}
+}
+
+// Then registers a callback to be called when the given Task resolves,
+// the callback is able to resolve a value of a different type.
+//
+// Go does not allow methods to declare their own type parameters,
+// hence this is a function and not a method.
+func Then[T, U any](t *Task[T], fn func(result T, t *Internal[U])) *Task[U] {
+	return From[U](t.Task.Then(func(result interface{}, t2 *task.Internal) {
+		v, err := cast[T](result)
+		if err != nil {
+			t2.Reject(err)
+			return
+		}
+		fn(v, &Internal[U]{Internal: t2})
+	}))
+}
+
+// Resolved returns a pre-resolved task
+func Resolved[T any](v T) *Task[T] {
+	return From[T](task.Resolved(v))
+}
+
+// Rejected returns a pre-rejected task
+func Rejected[T any](e error) *Task[T] {
+	return From[T](task.Rejected(e))
+}
+
+// ErrUnexpectedType is returned when a task resolved a value
+// that is not of the type the typed task expected.
+type ErrUnexpectedType struct {
+	Expected reflect.Type
+	Value    interface{}
+}
+
+func (e *ErrUnexpectedType) Error() string {
+	return fmt.Sprintf("typed: expected a value of type %v but got %T", e.Expected, e.Value)
+}
+
+// cast converts an untyped value to T, a nil value
+// (ie: the task did not resolve anything) becomes the zero value of T.
+func cast[T any](v interface{}) (T, error) {
+	var zero T
+	if v == nil {
+		return zero, nil
+	}
+	if tv, ok := v.(T); ok {
+		return tv, nil
+	}
+	return zero, goerr.Wrap(&ErrUnexpectedType{
+		Expected: reflect.TypeOf((*T)(nil)).Elem(),
+		Value:    v,
+	})
+}
+
+// untyped returns the embedded tasks so they can be used with the untyped API.
+func untyped[T any](awaitables []*Task[T]) []*task.Task {
+	tasks := make([]*task.Task, 0, len(awaitables))
+	for _, awaitable := range awaitables {
+		tasks = append(tasks, awaitable.Task)
+	}
+	return tasks
+}
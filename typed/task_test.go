@@ -0,0 +1,56 @@
+package typed_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/brad-jones/goasync/v2/await"
+	"github.com/brad-jones/goasync/v2/task"
+	"github.com/brad-jones/goasync/v2/typed"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResult(t *testing.T) {
+	v, err := typed.New(func(t *typed.Internal[string]) {
+		t.Resolve("hello")
+	}).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", v)
+}
+
+func TestFromUnexpectedType(t *testing.T) {
+	v, err := typed.From[int](task.Resolved("one")).Result()
+	assert.Equal(t, 0, v)
+	assert.ErrorAs(t, err, new(*typed.ErrUnexpectedType))
+}
+
+func TestThen(t *testing.T) {
+	v, err := typed.Then(typed.Resolved(2), func(result int, t *typed.Internal[string]) {
+		t.Resolve(string(rune('a' + result)))
+	}).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, "c", v)
+
+	// A value of the wrong type rejects the new task without calling the callback
+	called := false
+	_, err = typed.Then(typed.From[int](task.Resolved("one")), func(result int, t *typed.Internal[int]) {
+		called = true
+	}).Result()
+	assert.ErrorAs(t, err, new(*typed.ErrUnexpectedType))
+	assert.False(t, called)
+}
+
+func TestAll(t *testing.T) {
+	v, err := typed.All(typed.Resolved(1), typed.Resolved(2))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, v)
+
+	_, err = typed.All(typed.Resolved(1), typed.From[int](task.Resolved("two")))
+	assert.ErrorAs(t, err, new(*typed.ErrUnexpectedType))
+
+	_, err = typed.All(typed.Resolved(1), typed.Rejected[int](errors.New("boom")))
+	var failed *await.ErrTaskFailed
+	if assert.ErrorAs(t, err, &failed) {
+		assert.Equal(t, []int{1}, failed.Indexes)
+	}
+}